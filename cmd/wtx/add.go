@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/darkLord19/wtx/internal/git"
 	"github.com/darkLord19/wtx/internal/metadata"
 	"github.com/darkLord19/wtx/internal/validation"
 	"github.com/spf13/cobra"
@@ -11,13 +14,30 @@ import (
 
 var (
 	baseBranch string
+	addRemote  string
+	addSparse  string
+	addAt      string
+
+	addSubmodules bool
+	addLFS        bool
+	addNoIncludes bool
 )
 
 var addCmd = &cobra.Command{
 	Use:   "add <name> [branch]",
 	Short: "Create a new worktree",
-	Long:  "Create a new worktree with the specified name and optionally a branch name",
-	Args:  cobra.RangeArgs(1, 2),
+	Long: `Create a new worktree with the specified name and optionally a branch name.
+
+With --at, the worktree is created with a detached HEAD at a tag, commit or
+other ref instead of a branch. Detached worktrees are meant for reviewing or
+testing a release and are treated as ephemeral by 'wtx prune'.
+
+Ignored files listed in .wtxinclude, such as .env files, are copied or
+symlinked from the main worktree; see 'wtx refresh-includes --help'.`,
+	Example: `  wtx add feature-auth
+  wtx add fix feature/fix-login --from develop
+  wtx add release-check --at v1.2.0`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		branch := name
@@ -25,63 +45,158 @@ var addCmd = &cobra.Command{
 			branch = args[1]
 		}
 
+		if addAt != "" {
+			if len(args) == 2 || baseBranch != "" || addRemote != "" {
+				return fmt.Errorf("--at creates a detached worktree and cannot be combined with a branch, --from or --remote")
+			}
+			branch = ""
+		}
+
 		validator := validation.NewWorktreeValidator()
 		if err := validator.ValidateName(name); err != nil {
 			return err
 		}
-		if err := validator.ValidateBranchName(branch); err != nil {
-			return err
+		if branch != "" {
+			if err := validator.ValidateBranchName(branch); err != nil {
+				return err
+			}
 		}
 
-		fmt.Printf("Creating worktree '%s' for branch '%s'...\n", name, branch)
+		var sparsePaths []string
+		if addSparse != "" {
+			profiles, err := gitMgr.SparseProfiles(cfg.SparseProfiles)
+			if err != nil {
+				return err
+			}
+			paths, ok := profiles[addSparse]
+			if !ok {
+				return fmt.Errorf("unknown sparse profile '%s' (available: %s)", addSparse, strings.Join(git.ProfileNames(profiles), ", "))
+			}
+			// An empty profile still makes a sparse checkout of top-level files
+			sparsePaths = append([]string{}, paths...)
+		}
+
+		if addAt != "" {
+			fmt.Printf("Creating detached worktree '%s' at '%s'...\n", name, addAt)
+		} else {
+			fmt.Printf("Creating worktree '%s' for branch '%s'...\n", name, branch)
+		}
 
 		// Create worktree
-		path, err := gitMgr.Add(name, branch, baseBranch)
+		result, err := gitMgr.AddWithOptions(git.AddOptions{
+			Name:         name,
+			Branch:       branch,
+			Base:         baseBranch,
+			Remote:       addRemote,
+			SparsePaths:  sparsePaths,
+			At:           addAt,
+			SkipIncludes: addNoIncludes,
+			Submodules:   addSubmodules,
+			LFS:          addLFS,
+			// Cloning submodules and downloading LFS files can take a while
+			Progress: os.Stderr,
+		})
 		if err != nil {
 			return err
 		}
+		path := result.Path
 
 		fmt.Printf("✓ Created worktree: %s\n", name)
 		fmt.Printf("  Path: %s\n", path)
-		fmt.Printf("  Branch: %s\n", branch)
+		if addAt != "" {
+			fmt.Printf("  Detached at: %s (%.7s)\n", addAt, result.Commit)
+		} else {
+			fmt.Printf("  Branch: %s\n", branch)
+		}
+		if result.Upstream != "" {
+			fmt.Printf("  Tracking: %s\n", result.Upstream)
+		}
+		if addSparse != "" {
+			fmt.Printf("  Sparse: %s (%s)\n", addSparse, strings.Join(sparsePaths, ", "))
+		}
+
+		printIncludes(result.Included)
+		if result.IncludeErr != nil {
+			fmt.Printf("  ✗ %v\n", result.IncludeErr)
+		}
+
+		printPrepared(result)
 
 		// Save metadata
 		meta := &metadata.WorktreeMetadata{
-			Name:       name,
-			Path:       path,
-			Branch:     branch,
-			CreatedAt:  time.Now(),
-			LastOpened: time.Now(),
+			Name:          name,
+			Path:          path,
+			Branch:        branch,
+			CreatedAt:     time.Now(),
+			LastOpened:    time.Now(),
+			SparseProfile: addSparse,
+			DetachedAt:    addAt,
 		}
 		metaStore.Add(meta)
 		if err := metaStore.Save(); err != nil {
 			fmt.Printf("Warning: failed to save metadata: %v\n", err)
 		}
 
-		// Ask if user wants to open now
-		fmt.Print("\nOpen in editor now? [Y/n]: ")
-		var response string
-		// We explicitly ignore the error from Scanln here because if the user just hits Enter,
-		// Scanln returns an error (unexpected newline) but we want to treat that as "empty input"
-		// which results in the default behavior (opening the editor).
-		_, _ = fmt.Scanln(&response)
+		return promptOpen(path)
+	},
+}
 
-		if response == "" || response == "y" || response == "Y" {
-			ed, err := edDetector.GetPreferred()
-			if err != nil {
-				return err
-			}
+// promptOpen asks whether to open a new worktree and opens it in the
+// preferred editor
+func promptOpen(path string) error {
+	fmt.Print("\nOpen in editor now? [Y/n]: ")
+	var response string
+	// We explicitly ignore the error from Scanln here because if the user just hits Enter,
+	// Scanln returns an error (unexpected newline) but we want to treat that as "empty input"
+	// which results in the default behavior (opening the editor).
+	_, _ = fmt.Scanln(&response)
 
-			fmt.Printf("Opening in %s...\n", ed.Name())
-			if err := ed.Open(path, cfg.ReuseWindow); err != nil {
-				return fmt.Errorf("failed to open editor: %w", err)
-			}
+	if response == "" || response == "y" || response == "Y" {
+		ed, err := edDetector.GetPreferred()
+		if err != nil {
+			return err
 		}
 
-		return nil
-	},
+		fmt.Printf("Opening in %s...\n", ed.Name())
+		if err := ed.Open(path, cfg.ReuseWindow); err != nil {
+			return fmt.Errorf("failed to open editor: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func init() {
-	addCmd.Flags().StringVarP(&baseBranch, "from", "f", "main", "Base branch to create from")
+	addCmd.Flags().StringVarP(&addRemote, "remote", "r", "", "Remote to take an existing branch from (default: search all remotes)")
+	addCmd.Flags().StringVarP(&baseBranch, "from", "f", "", "Base branch to create from (default: the repository's default branch)")
+	addCmd.Flags().StringVarP(&addSparse, "sparse", "s", "", "Create a sparse checkout from a profile in sparse_profiles or .wtxsparse")
+	addCmd.Flags().StringVar(&addAt, "at", "", "Create a detached worktree at a tag, commit or ref instead of a branch")
+	addCmd.Flags().BoolVar(&addSubmodules, "submodules", false, "Initialize submodules recursively after creating the worktree")
+	addCmd.Flags().BoolVar(&addLFS, "lfs", false, "Download Git LFS files after creating the worktree")
+	addCmd.Flags().BoolVar(&addNoIncludes, "no-includes", false, "Don't copy or link the ignored files listed in .wtxinclude")
+}
+
+// printPrepared reports the submodules and LFS files fetched into a new
+// worktree, or points out that it needs them. Failures are reported but
+// leave the worktree in place.
+func printPrepared(result *git.AddResult) {
+	switch {
+	case !result.HasSubmodules:
+	case result.SubmoduleErr != nil:
+		fmt.Printf("  ✗ %v\n", result.SubmoduleErr)
+	case result.SubmodulesUpdated:
+		fmt.Println("  ✓ Submodules initialized")
+	default:
+		fmt.Println("  Note: this repository has submodules; use --submodules or run 'git submodule update --init --recursive'")
+	}
+
+	switch {
+	case !result.UsesLFS:
+	case result.LFSErr != nil:
+		fmt.Printf("  ✗ %v\n", result.LFSErr)
+	case result.LFSPulled:
+		fmt.Println("  ✓ LFS files downloaded")
+	default:
+		fmt.Println("  Note: this repository uses Git LFS; use --lfs or run 'git lfs pull'")
+	}
 }
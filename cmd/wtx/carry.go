@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var (
+	carryUntracked bool
+	carryKeep      bool
+)
+
+var carryCmd = &cobra.Command{
+	Use:   "carry <from> <to>",
+	Short: "Move uncommitted changes to another worktree",
+	Long: `Move staged and unstaged changes from one worktree to another.
+
+The changes are captured as a patch, like git stash, and applied to the
+target with a 3-way merge, so they carry over even if the worktrees are at
+different commits. Carried changes are left unstaged in the target.
+
+The source is only cleaned after the patch applied without conflicts. If
+there are conflicts they are left in the target to resolve, the rest of the
+target's index is restored, and the source keeps its changes. A backup of every carried patch is kept in the git
+directory under wtx-carry/.`,
+	Example: `  wtx carry feature-a feature-b
+  wtx carry feature-a feature-b --untracked
+  wtx carry feature-a feature-b --keep`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := findWorktree(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := findWorktree(args[1])
+		if err != nil {
+			return err
+		}
+		if from.Path == to.Path {
+			return fmt.Errorf("nothing to carry: '%s' is the same worktree", from.Name)
+		}
+
+		result, err := gitMgr.Carry(from.Path, to.Path, git.CarryOptions{
+			Untracked: carryUntracked,
+			Keep:      carryKeep,
+		})
+		if err != nil {
+			if result != nil {
+				fmt.Printf("  Backup: %s\n", result.Backup)
+			}
+			return err
+		}
+
+		if len(result.Conflicts) > 0 {
+			fmt.Printf("✗ Conflicts carrying changes from %s to %s:\n", from.Name, to.Name)
+			for _, file := range result.Conflicts {
+				fmt.Printf("  %s\n", file)
+			}
+			fmt.Printf("  Backup: %s\n", result.Backup)
+			return fmt.Errorf("resolve the conflicts in '%s'; '%s' was left unchanged", to.Name, from.Name)
+		}
+
+		fmt.Printf("✓ Carried %d file(s) from %s to %s\n", len(result.Files), from.Name, to.Name)
+		for _, file := range result.Files {
+			fmt.Printf("  %s\n", file)
+		}
+		if result.Cleaned {
+			fmt.Printf("  %s is now clean\n", from.Name)
+		} else {
+			fmt.Printf("  %s still has its changes\n", from.Name)
+		}
+		fmt.Printf("  Backup: %s\n", result.Backup)
+		return nil
+	},
+}
+
+func init() {
+	carryCmd.Flags().BoolVarP(&carryUntracked, "untracked", "u", false, "Carry untracked files too")
+	carryCmd.Flags().BoolVar(&carryKeep, "keep", false, "Leave the changes in the source worktree as well")
+}
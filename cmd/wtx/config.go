@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/darkLord19/wtx/internal/git"
 	"github.com/darkLord19/wtx/internal/tui"
 	"github.com/darkLord19/wtx/internal/validation"
 	"github.com/spf13/cobra"
@@ -38,6 +39,18 @@ var configCmd = &cobra.Command{
 			fmt.Printf("Reuse window:   %v\n", cfg.ReuseWindow)
 			fmt.Printf("Worktree dir:   %s\n", cfg.WorktreeDir)
 			fmt.Printf("Auto start dev: %v\n", cfg.AutoStartDev)
+			fmt.Printf("Status workers: %d\n", cfg.StatusWorkers)
+			fmt.Printf("Delete branch:  %v\n", cfg.DeleteBranch)
+			if cfg.PreferredRemote != "" {
+				fmt.Printf("Preferred remote: %s\n", cfg.PreferredRemote)
+			}
+			if gitMgr != nil {
+				if override := gitMgr.DefaultBranchOverride(); override != "" {
+					fmt.Printf("Default branch: %s (this repo)\n", override)
+				} else {
+					fmt.Printf("Default branch: %s (auto-detected)\n", gitMgr.DefaultBranch())
+				}
+			}
 
 			if len(cfg.CustomCommands) > 0 {
 				fmt.Println("\nCustom commands:")
@@ -111,7 +124,7 @@ var configCmd = &cobra.Command{
 
 		case "worktree_dir":
 			if len(args) != 2 {
-				return fmt.Errorf("usage: wtx config worktree_dir <path>")
+				return fmt.Errorf("usage: wtx config worktree_dir <path|template|sibling|nested|central>")
 			}
 			val := args[1]
 
@@ -119,12 +132,105 @@ var configCmd = &cobra.Command{
 			if err := validator.ValidatePath(val); err != nil {
 				return fmt.Errorf("invalid path: %w", err)
 			}
+			if err := git.ValidateLayout(val); err != nil {
+				return fmt.Errorf("invalid worktree_dir: %w", err)
+			}
 
 			cfg.WorktreeDir = val
 			if err := cfg.Save(); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 			fmt.Printf("Set worktree_dir to '%s'\n", val)
+			if gitMgr != nil {
+				gitMgr.SetWorktreeDir(val)
+				if example, err := gitMgr.WorktreePath("example", "example"); err == nil {
+					fmt.Printf("New worktrees will be created like: %s\n", example)
+				}
+			}
+			return nil
+
+		case "default_branch":
+			// Stored per repository in git config, not in the global config file
+			if len(args) != 2 {
+				return fmt.Errorf("usage: wtx config default_branch <branch|auto>")
+			}
+			if gitMgr == nil {
+				return fmt.Errorf("default_branch can only be set inside a git repository")
+			}
+			val := args[1]
+
+			if val == "auto" {
+				if err := gitMgr.SetDefaultBranch(""); err != nil {
+					return err
+				}
+				fmt.Printf("Cleared default_branch override, using '%s'\n", gitMgr.DefaultBranch())
+				return nil
+			}
+
+			validator := validation.NewWorktreeValidator()
+			if err := validator.ValidateBranchName(val); err != nil {
+				return err
+			}
+			if err := gitMgr.SetDefaultBranch(val); err != nil {
+				return err
+			}
+			fmt.Printf("Set default_branch to '%s' for this repository\n", val)
+			return nil
+
+		case "preferred_remote":
+			if len(args) != 2 {
+				return fmt.Errorf("usage: wtx config preferred_remote <remote|none>")
+			}
+			val := args[1]
+			if val == "none" {
+				val = ""
+			}
+
+			cfg.PreferredRemote = val
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			if gitMgr != nil {
+				gitMgr.SetPreferredRemote(val)
+			}
+			if val == "" {
+				fmt.Println("Cleared preferred_remote")
+			} else {
+				fmt.Printf("Set preferred_remote to '%s'\n", val)
+			}
+			return nil
+
+		case "status_workers":
+			if len(args) != 2 {
+				return fmt.Errorf("usage: wtx config status_workers <n>")
+			}
+			val, err := strconv.Atoi(args[1])
+			if err != nil || val < 1 {
+				return fmt.Errorf("invalid worker count: %s (must be a positive integer)", args[1])
+			}
+			cfg.StatusWorkers = val
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			if gitMgr != nil {
+				gitMgr.SetStatusWorkers(val)
+			}
+			fmt.Printf("Set status_workers to %d\n", val)
+			return nil
+
+		case "delete_branch":
+			if len(args) != 2 {
+				return fmt.Errorf("usage: wtx config delete_branch <true|false>")
+			}
+			val, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid boolean value: %s", args[1])
+			}
+			cfg.DeleteBranch = val
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			fmt.Printf("Set delete_branch to %v\n", val)
 			return nil
 
 		case "auto_start_dev":
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/editor"
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var (
+	diffStat        bool
+	diffNameOnly    bool
+	diffUncommitted bool
+	diffOpen        string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <a> [b]",
+	Short: "Compare two worktrees",
+	Long: `Show the commits and file changes between two worktrees.
+
+Without b, a is compared with the main worktree. By default the worktrees'
+HEAD commits are compared; --uncommitted includes each side's staged,
+unstaged and untracked changes as well.
+
+--open shows a single file side by side in the preferred editor's diff view.`,
+	Example: `  wtx diff feature-a feature-b
+  wtx diff feature-a feature-b --stat
+  wtx diff feature-a --uncommitted --name-only
+  wtx diff feature-a feature-b --open src/auth.go`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := findWorktree(args[0])
+		if err != nil {
+			return err
+		}
+
+		var b *git.Worktree
+		if len(args) == 2 {
+			b, err = findWorktree(args[1])
+		} else {
+			b, err = mainWorktree()
+		}
+		if err != nil {
+			return err
+		}
+		if a.Path == b.Path {
+			return fmt.Errorf("nothing to compare: '%s' is the same worktree", a.Name)
+		}
+
+		from, to := a.Head, b.Head
+		if diffUncommitted {
+			if from, err = gitMgr.Snapshot(a.Path); err != nil {
+				return err
+			}
+			if to, err = gitMgr.Snapshot(b.Path); err != nil {
+				return err
+			}
+		}
+
+		if diffOpen != "" {
+			return openFileDiff(a, b, from, to, diffOpen)
+		}
+
+		if diffUncommitted {
+			fmt.Printf("Comparing %s (%.7s) with %s (%.7s), including uncommitted changes\n\n", a.Name, a.Head, b.Name, b.Head)
+		} else {
+			fmt.Printf("Comparing %s (%.7s) with %s (%.7s)\n\n", a.Name, a.Head, b.Name, b.Head)
+		}
+
+		onlyA, onlyB, err := gitMgr.DivergentCommits(a.Head, b.Head)
+		if err != nil {
+			return err
+		}
+		printCommits(a.Name, onlyA)
+		printCommits(b.Name, onlyB)
+		if len(onlyA) == 0 && len(onlyB) == 0 {
+			fmt.Println("Both worktrees are at the same commit")
+		}
+		fmt.Println()
+
+		diff, err := gitMgr.Diff(from, to, git.DiffOptions{
+			Stat:     diffStat,
+			NameOnly: diffNameOnly,
+			Color:    isTerminal(os.Stdout),
+		})
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			fmt.Println("No file differences")
+			return nil
+		}
+		fmt.Print(diff)
+		return nil
+	},
+}
+
+// mainWorktree returns the repository's main worktree
+func mainWorktree() (*git.Worktree, error) {
+	worktrees, err := gitMgr.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			wt := wt
+			return &wt, nil
+		}
+	}
+	return nil, fmt.Errorf("main worktree not found")
+}
+
+// printCommits lists the commits only one side of a comparison has
+func printCommits(name string, commits []git.CommitSummary) {
+	if len(commits) == 0 {
+		return
+	}
+	fmt.Printf("%d commit(s) only on %s:\n", len(commits), name)
+	for _, c := range commits {
+		fmt.Printf("  %s %s\n", c.Hash, c.Subject)
+	}
+}
+
+// openFileDiff opens one file from both sides in the editor's diff view.
+// Uncommitted comparisons open the worktree files themselves so they can be
+// edited; otherwise each side's committed version is exported to a temporary
+// file, which is left in place because GUI editors read it asynchronously.
+func openFileDiff(a, b *git.Worktree, from, to, file string) error {
+	ed, err := edDetector.GetPreferred()
+	if err != nil {
+		return err
+	}
+	differ, ok := ed.(editor.DiffOpener)
+	if !ok {
+		return fmt.Errorf("%s has no diff view; set editor to vscode, cursor, vscodium, neovim or vim", ed.Name())
+	}
+
+	tmpDir, err := os.MkdirTemp("", "wtx-diff-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	side := func(wt *git.Worktree, rev string) (string, bool, error) {
+		if diffUncommitted {
+			path := filepath.Join(wt.Path, file)
+			if _, err := os.Stat(path); err == nil {
+				return path, true, nil
+			}
+		}
+
+		// Keep the file name so the editor picks the right syntax
+		dir := filepath.Join(tmpDir, wt.Name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", false, err
+		}
+		dest := filepath.Join(dir, filepath.Base(file))
+		found, err := gitMgr.ExportFile(rev, file, dest)
+		if err != nil || found {
+			return dest, found, err
+		}
+		// Missing files show as empty, like an added or deleted file
+		return dest, false, os.WriteFile(dest, nil, 0644)
+	}
+
+	left, foundA, err := side(a, from)
+	if err != nil {
+		return err
+	}
+	right, foundB, err := side(b, to)
+	if err != nil {
+		return err
+	}
+	if !foundA && !foundB {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("'%s' not found in %s or %s", file, a.Name, b.Name)
+	}
+
+	fmt.Printf("Opening %s in %s (%s ↔ %s)...\n", file, ed.Name(), a.Name, b.Name)
+	return differ.OpenDiff(left, right)
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show a diffstat instead of the patch")
+	diffCmd.Flags().BoolVar(&diffNameOnly, "name-only", false, "Show only the names of changed files")
+	diffCmd.Flags().BoolVarP(&diffUncommitted, "uncommitted", "u", false, "Include each worktree's uncommitted and untracked changes")
+	diffCmd.Flags().StringVar(&diffOpen, "open", "", "Open a single file in the editor's diff view")
+	diffCmd.MarkFlagsMutuallyExclusive("stat", "name-only")
+}
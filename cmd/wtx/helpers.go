@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+// findWorktree looks up a worktree by name
+func findWorktree(name string) (*git.Worktree, error) {
+	worktrees, err := gitMgr.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wt := range worktrees {
+		if wt.Name == name {
+			wt := wt
+			return &wt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("worktree '%s' not found", name)
+}
+
+// branchLabel returns a worktree's branch, or where its HEAD is detached
+func branchLabel(wt git.Worktree) string {
+	if !wt.Detached {
+		return wt.Branch
+	}
+	if meta, ok := metaStore.Get(wt.Name); ok && meta.DetachedAt != "" {
+		return fmt.Sprintf("(detached %s @ %.7s)", meta.DetachedAt, wt.Head)
+	}
+	return fmt.Sprintf("(detached %.7s)", wt.Head)
+}
+
+// commitSummary describes a worktree's last commit on one line, e.g.
+// "a1b2c3d Fix login redirect · Alice · 2d ago · 3 since main"
+func commitSummary(info *git.CommitInfo) string {
+	subject := info.Subject
+	if runes := []rune(subject); len(runes) > 60 {
+		subject = string(runes[:59]) + "…"
+	}
+
+	parts := []string{fmt.Sprintf("%.7s %s", info.Hash, subject), info.Author, info.Age(time.Now())}
+	if info.Base != "" && info.SinceBase > 0 {
+		parts = append(parts, fmt.Sprintf("%d since %s", info.SinceBase, info.Base))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// deleteBranchFlags registers --delete-branch and -D on a command that
+// removes worktrees
+func deleteBranchFlags(cmd *cobra.Command, deleteBranch, force *bool) {
+	cmd.Flags().BoolVar(deleteBranch, "delete-branch", false, "Also delete the branch if it is merged or fully pushed (default: delete_branch config)")
+	cmd.Flags().BoolVarP(force, "force-delete-branch", "D", false, "Also delete the branch even if it has unmerged, unpushed commits")
+}
+
+// wantDeleteBranch reports whether branches should be deleted along with
+// their worktrees: -D or --delete-branch, falling back to the config
+func wantDeleteBranch(cmd *cobra.Command, deleteBranch, force bool) bool {
+	if force {
+		return true
+	}
+	if cmd.Flags().Changed("delete-branch") {
+		return deleteBranch
+	}
+	return cfg.DeleteBranch
+}
+
+// deleteWorktreeBranch deletes the branch of a removed worktree. It returns
+// why the branch was kept, or "" if it was deleted.
+func deleteWorktreeBranch(wt git.Worktree, force bool) string {
+	err := gitMgr.DeleteBranch(wt.Branch, gitMgr.DefaultBranch(), force)
+	if err == nil {
+		return ""
+	}
+
+	var unsafe *git.UnsafeBranchError
+	if errors.As(err, &unsafe) {
+		return unsafe.Reason + " (use -D to delete it anyway)"
+	}
+	return err.Error()
+}
+
+// lockedError explains why a locked worktree can't be removed
+func lockedError(wt *git.Worktree) error {
+	reason := ""
+	if wt.LockReason != "" {
+		reason = fmt.Sprintf(": %s", wt.LockReason)
+	}
+	return fmt.Errorf("worktree '%s' is locked%s\nUse --allow-locked to remove it anyway, or run 'wtx unlock %s' first", wt.Name, reason, wt.Name)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var refreshIncludesForce bool
+
+var refreshIncludesCmd = &cobra.Command{
+	Use:   "refresh-includes <name>",
+	Short: "Copy or link ignored files from .wtxinclude into a worktree again",
+	Long: `Bring a worktree's ignored local files back in sync with the main worktree.
+
+.wtxinclude at the repository root lists gitignore patterns for files like
+.env, local certificates and IDE settings that new worktrees need. Matching
+ignored files are copied from the main worktree, or symlinked to it when
+listed under a [symlink] section:
+
+  .env
+  certs/*.pem
+
+  [symlink]
+  node_modules/
+
+'wtx add' applies .wtxinclude when it creates a worktree. refresh-includes
+brings in files that are missing since; files that differ from the main
+worktree are left alone unless --force is given.`,
+	Example: `  wtx refresh-includes feature-auth
+  wtx refresh-includes feature-auth --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wt, err := findWorktree(args[0])
+		if err != nil {
+			return err
+		}
+		if wt.IsMain {
+			return fmt.Errorf("'%s' is the main worktree, which files are included from", wt.Name)
+		}
+
+		patterns, err := gitMgr.Includes()
+		if err != nil {
+			return err
+		}
+		if patterns == nil {
+			return fmt.Errorf("no %s in the main worktree", git.IncludeFile)
+		}
+
+		fmt.Printf("Refreshing %s files in '%s'...\n", git.IncludeFile, wt.Name)
+		included, err := gitMgr.ApplyIncludes(wt.Path, refreshIncludesForce)
+		printIncludes(included)
+		if err != nil {
+			return err
+		}
+
+		unchanged := 0
+		for _, inc := range included {
+			if inc.Status == git.IncludeUnchanged {
+				unchanged++
+			}
+		}
+		switch {
+		case len(included) == 0:
+			fmt.Printf("No ignored files match %s\n", git.IncludeFile)
+		case unchanged > 0:
+			fmt.Printf("%d path(s) already up to date\n", unchanged)
+		}
+		return nil
+	},
+}
+
+// printIncludes reports what was brought in from .wtxinclude
+func printIncludes(included []git.IncludedPath) {
+	for _, inc := range included {
+		verb := "Copied"
+		if inc.Mode == git.IncludeSymlink {
+			verb = "Linked"
+		}
+
+		switch inc.Status {
+		case git.IncludeCreated:
+			fmt.Printf("  ✓ %s %s\n", verb, inc.Path)
+		case git.IncludeUpdated:
+			fmt.Printf("  ✓ %s %s (replaced)\n", verb, inc.Path)
+		case git.IncludeSkipped:
+			fmt.Printf("  ✗ Skipped %s: differs from the main worktree (use 'wtx refresh-includes --force')\n", inc.Path)
+		}
+	}
+}
+
+func init() {
+	refreshIncludesCmd.Flags().BoolVarP(&refreshIncludesForce, "force", "f", false, "Replace files that differ from the main worktree")
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
 )
 
 var listCmd = &cobra.Command{
@@ -21,34 +24,86 @@ var listCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Printf("%-20s %-30s %-10s %s\n", "NAME", "BRANCH", "STATUS", "PATH")
-		fmt.Println("────────────────────────────────────────────────────────────────────────────")
+		// Last commits are a nice-to-have; list without them if git fails
+		commits, _ := gitMgr.CommitInfos(worktrees, gitMgr.DefaultBranch())
+
+		fmt.Printf("%-20s %-30s %-16s %-16s %s\n", "NAME", "BRANCH", "STATUS", "CHANGES", "PATH")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────────────────────")
 
 		for _, wt := range worktrees {
-			status, _ := gitMgr.GetStatus(wt.Path)
+			problem := gitMgr.Diagnose(wt)
+			var status *git.Status
+			if problem == git.ProblemNone {
+				status, _ = gitMgr.GetStatus(wt.Path)
+			}
 
 			statusStr := "●"
 			statusText := "clean"
-			if status != nil && !status.Clean {
+			details := ""
+			if problem != git.ProblemNone {
 				statusStr = "✗"
-				statusText = "dirty"
+				statusText = problem.String()
+				details = "run wtx repair"
+			} else if status != nil {
+				statusText = status.State()
+				if status.Operation != git.OperationNone || status.Conflicted > 0 {
+					statusStr = "⚠"
+				} else if !status.Clean {
+					statusStr = "✗"
+				}
+				details = formatStatusDetails(status)
 			}
 
 			mainIndicator := ""
 			if wt.IsMain {
 				mainIndicator = " ⭐"
 			}
+			if wt.Locked {
+				mainIndicator += " 🔒 locked"
+				if wt.LockReason != "" {
+					mainIndicator += fmt.Sprintf(" (%s)", wt.LockReason)
+				}
+			}
+			if wt.Prunable {
+				mainIndicator += " ⚠ prunable"
+				if wt.PrunableReason != "" {
+					mainIndicator += fmt.Sprintf(" (%s)", wt.PrunableReason)
+				}
+			}
 
-			fmt.Printf("%-20s %-30s %s %-8s %s%s\n",
+			fmt.Printf("%-20s %-30s %s %-14s %-16s %s%s\n",
 				wt.Name,
-				wt.Branch,
+				branchLabel(wt),
 				statusStr,
 				statusText,
+				details,
 				wt.Path,
 				mainIndicator,
 			)
+			if info, ok := commits[wt.Head]; ok {
+				fmt.Printf("%-20s %s\n", "", commitSummary(info))
+			}
 		}
 
 		return nil
 	},
 }
+
+// formatStatusDetails returns change counts and upstream tracking info for
+// a single line of output, e.g. "+1 ~2 ↑3 ↓1"
+func formatStatusDetails(status *git.Status) string {
+	var parts []string
+	if changes := status.ChangeSummary(); changes != "" {
+		parts = append(parts, changes)
+	}
+	if status.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", status.Ahead))
+	}
+	if status.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", status.Behind))
+	}
+	if status.UpstreamGone {
+		parts = append(parts, "gone")
+	}
+	return strings.Join(parts, " ")
+}
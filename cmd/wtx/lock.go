@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lockReason string
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock <name>",
+	Short: "Lock a worktree",
+	Long:  "Lock a worktree to protect it from removal, pruning and moving. Locked worktrees are skipped by 'wtx rm' and 'wtx prune' unless --allow-locked is given.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		wt, err := findWorktree(name)
+		if err != nil {
+			return err
+		}
+
+		if wt.IsMain {
+			return fmt.Errorf("the main worktree cannot be locked")
+		}
+		if wt.Locked {
+			return fmt.Errorf("worktree '%s' is already locked", name)
+		}
+
+		if err := gitMgr.Lock(name, lockReason); err != nil {
+			return err
+		}
+
+		if lockReason != "" {
+			fmt.Printf("🔒 Locked worktree: %s (%s)\n", name, lockReason)
+		} else {
+			fmt.Printf("🔒 Locked worktree: %s\n", name)
+		}
+		return nil
+	},
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <name>",
+	Short: "Unlock a worktree",
+	Long:  "Remove the lock from a worktree so it can be removed or pruned again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		wt, err := findWorktree(name)
+		if err != nil {
+			return err
+		}
+
+		if !wt.Locked {
+			return fmt.Errorf("worktree '%s' is not locked", name)
+		}
+
+		if err := gitMgr.Unlock(name); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Unlocked worktree: %s\n", name)
+		return nil
+	},
+}
+
+func init() {
+	lockCmd.Flags().StringVarP(&lockReason, "reason", "r", "", "Why the worktree is locked")
+}
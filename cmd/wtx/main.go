@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -21,6 +22,8 @@ var (
 	edDetector *editor.Detector
 	fullTUI    bool
 	isFirstRun bool
+	traceGit   bool
+	gitTimeout time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -35,6 +38,8 @@ func init() {
 
 	// Add flags
 	rootCmd.Flags().BoolVarP(&fullTUI, "tui", "t", false, "Launch full TUI with tabs (worktrees, manage, settings)")
+	rootCmd.PersistentFlags().BoolVar(&traceGit, "trace", false, "Log every git command and its duration to the wtx log")
+	rootCmd.PersistentFlags().DurationVar(&gitTimeout, "git-timeout", 30*time.Second, "Timeout for read-only git commands (0 disables)")
 
 	// Add commands
 	rootCmd.AddCommand(listCmd)
@@ -46,6 +51,16 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(manageCmd)
 	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(sparseCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(carryCmd)
+	rootCmd.AddCommand(refreshIncludesCmd)
 }
 
 func initConfig() {
@@ -71,14 +86,12 @@ func initConfig() {
 	cfg = loadResult.Config
 	isFirstRun = loadResult.IsFirstRun
 
-	// Find git repo
-	repoPath, err := git.GetRootPath()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	runner := git.NewExecRunner()
+	runner.Timeout = gitTimeout
+	runner.Trace = traceGit
 
-	repo, err := git.FindRepo(repoPath)
+	// Find git repo (works from linked worktrees and bare repositories)
+	repo, err := git.FindRepoWithRunner(runner, ".")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -86,7 +99,11 @@ func initConfig() {
 
 	// Initialize managers
 	gitMgr = git.NewManager(repo)
-	metaStore, err = metadata.Load(repoPath)
+	gitMgr.SetRunner(runner)
+	gitMgr.SetWorktreeDir(cfg.WorktreeDir)
+	gitMgr.SetPreferredRemote(cfg.PreferredRemote)
+	gitMgr.SetStatusWorkers(cfg.StatusWorkers)
+	metaStore, err = metadata.LoadFromGitDir(repo.Path, repo.GitDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading metadata: %v\n", err)
 		os.Exit(1)
@@ -10,7 +10,7 @@ var manageCmd = &cobra.Command{
 	Short: "Launch worktree management TUI",
 	Long:  "Interactive TUI for creating, deleting, and pruning worktrees",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tui.RunWorktreeManager(gitMgr, metaStore)
+		return tui.RunWorktreeManager(gitMgr, metaStore, cfg)
 	},
 }
 
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveBranch string
+)
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <name> <new-name|path>",
+	Short: "Rename or relocate a worktree",
+	Long: `Rename or relocate a worktree, keeping its wtx metadata.
+
+A bare name renames the worktree in place. Anything that looks like a path
+(contains a separator or starts with . or ~) moves it there instead.
+Use --branch to rename the checked out branch as well.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, dest := args[0], args[1]
+
+		wt, err := findWorktree(name)
+		if err != nil {
+			return err
+		}
+
+		if wt.IsMain {
+			return fmt.Errorf("the main worktree cannot be moved")
+		}
+		if wt.Locked {
+			return fmt.Errorf("worktree '%s' is locked\nRun 'wtx unlock %s' before moving it", name, name)
+		}
+
+		validator := validation.NewWorktreeValidator()
+		if moveBranch != "" {
+			if wt.Detached {
+				return fmt.Errorf("worktree '%s' has no branch to rename (detached HEAD)", name)
+			}
+			if err := validator.ValidateBranchName(moveBranch); err != nil {
+				return err
+			}
+		}
+
+		newPath, err := git.MoveDestination(wt.Path, dest)
+		if err != nil {
+			return err
+		}
+		newName := filepath.Base(newPath)
+		if err := validator.ValidateName(newName); err != nil {
+			return err
+		}
+		if newName != name {
+			if err := checkNameFree(newName); err != nil {
+				return err
+			}
+		}
+
+		if newPath != wt.Path {
+			fmt.Printf("Moving worktree '%s' to %s...\n", name, newPath)
+			if err := gitMgr.Move(name, newPath); err != nil {
+				return err
+			}
+		}
+
+		// Carry history, ports and dev command over to the new name as soon
+		// as the worktree has moved, so a failed branch rename can't leave
+		// them behind
+		metaStore.Rename(name, newName, newPath)
+
+		branch := wt.Branch
+		var branchErr error
+		if moveBranch != "" && moveBranch != wt.Branch {
+			if branchErr = gitMgr.RenameBranch(wt.Branch, moveBranch); branchErr == nil {
+				branch = moveBranch
+			}
+		}
+
+		if meta, ok := metaStore.Get(newName); ok {
+			meta.Branch = branch
+		}
+		if err := metaStore.Save(); err != nil {
+			fmt.Printf("Warning: failed to update metadata: %v\n", err)
+		}
+
+		fmt.Printf("✓ Moved worktree: %s → %s\n", name, newName)
+		fmt.Printf("  Path: %s\n", newPath)
+		if branch != wt.Branch {
+			fmt.Printf("  Branch: %s → %s\n", wt.Branch, branch)
+		}
+		if branchErr != nil {
+			return fmt.Errorf("moved, but failed to rename branch: %w", branchErr)
+		}
+		return nil
+	},
+}
+
+// checkNameFree fails if another worktree, or another worktree's metadata,
+// already uses name
+func checkNameFree(name string) error {
+	worktrees, err := gitMgr.List()
+	if err != nil {
+		return err
+	}
+	for _, wt := range worktrees {
+		if wt.Name == name {
+			return fmt.Errorf("a worktree named '%s' already exists at %s", name, wt.Path)
+		}
+	}
+	if _, ok := metaStore.Get(name); ok {
+		return fmt.Errorf("metadata for a worktree named '%s' already exists\nRun 'wtx repair' to clean up stale entries", name)
+	}
+	return nil
+}
+
+func init() {
+	mvCmd.Flags().StringVarP(&moveBranch, "branch", "b", "", "Also rename the worktree's branch")
+}
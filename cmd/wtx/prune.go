@@ -2,93 +2,309 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/prune"
 )
 
 var (
-	staleDays int
+	staleDays        int
+	pruneOlderThan   string
+	pruneMerged      bool
+	pruneGone        bool
+	pruneDetached    bool
+	pruneAllowLocked bool
+	pruneDryRun      bool
+	pruneYes         bool
+	pruneJSON        bool
+
+	pruneDeleteBranch      bool
+	pruneForceDeleteBranch bool
 )
 
 var pruneCmd = &cobra.Command{
 	Use:   "prune",
 	Short: "Clean up stale worktrees",
-	Long:  "Remove worktrees that haven't been opened in a specified number of days (default: 30)",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get stale worktrees
-		staleNames := metaStore.GetStale(staleDays)
+	Long: `Remove worktrees that are no longer needed.
 
-		if len(staleNames) == 0 {
-			fmt.Println("No stale worktrees found")
-			return nil
+Worktrees are selected if they match any of the given criteria:
+  --merged       branch is merged into the default branch
+  --gone         upstream branch was deleted (e.g. after the PR merged)
+  --detached     created detached with 'wtx add --at'
+  --older-than   not opened for the given age (e.g. 30d, 2w)
+
+Without criteria, detached worktrees and worktrees not opened in 30 days are
+selected. Only clean, unlocked worktrees are removed, and worktrees with
+commits that aren't on any remote are kept unless their branch is merged or
+its upstream is gone. Detached worktrees are kept if they have commits that
+aren't on any branch or tag.
+
+With --delete-branch (or delete_branch in the config) each removed worktree's
+branch is deleted too, if it is merged or fully pushed; -D deletes it
+regardless. The report lists which branches were deleted and which were kept.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := prunePolicy(cmd)
+		if err != nil {
+			return err
 		}
 
-		// Check which ones are clean
-		cleanStale := []string{}
 		worktrees, err := gitMgr.List()
 		if err != nil {
 			return err
 		}
 
-		for _, name := range staleNames {
-			for _, wt := range worktrees {
-				if wt.Name == name {
-					clean, _ := gitMgr.IsClean(wt.Path)
-					if clean {
-						cleanStale = append(cleanStale, name)
-					}
-					break
-				}
+		result := prune.Evaluate(gitMgr, metaStore, worktrees, policy, time.Now())
+
+		// Never block on a prompt nobody can answer, but there's nothing to
+		// confirm when nothing would be removed
+		if len(result.Candidates) > 0 {
+			if err := pruneConfirmError(isTerminal(os.Stdin)); err != nil {
+				return err
 			}
 		}
 
-		if len(cleanStale) == 0 {
-			fmt.Printf("No clean stale worktrees found (>%d days old)\n", staleDays)
-			return nil
+		report := newPruneReport(policy, result)
+
+		if !pruneJSON {
+			printPruneResult(policy, result)
 		}
 
-		fmt.Printf("Stale worktrees (clean, >%d days old):\n\n", staleDays)
-		for _, name := range cleanStale {
-			meta, _ := metaStore.Get(name)
-			if meta != nil {
-				fmt.Printf("  • %s (last opened: %s)\n", name, meta.LastOpened.Format("2006-01-02"))
-			} else {
-				fmt.Printf("  • %s\n", name)
+		if len(result.Candidates) == 0 || pruneDryRun {
+			if pruneJSON {
+				return printJSON(report)
 			}
+			return nil
 		}
 
-		fmt.Print("\nDelete all? [y/N]: ")
-		var response string
-		if _, err := fmt.Scanln(&response); err != nil {
-			return nil // Treat input error as cancel
-		}
+		if !pruneYes {
+			fmt.Print("\nDelete all? [y/N]: ")
+			var response string
+			if _, err := fmt.Scanln(&response); err != nil {
+				return nil // Treat input error as cancel
+			}
 
-		if response != "y" && response != "Y" {
-			fmt.Println("Cancelled")
-			return nil
+			if response != "y" && response != "Y" {
+				fmt.Println("Cancelled")
+				return nil
+			}
 		}
 
 		// Delete them
-		removed := 0
-		for _, name := range cleanStale {
-			if err := gitMgr.Remove(name, false); err != nil {
-				fmt.Printf("⚠  Failed to remove %s: %v\n", name, err)
+		deleteBranches := wantDeleteBranch(cmd, pruneDeleteBranch, pruneForceDeleteBranch)
+		deletedBranches := 0
+		report.WouldRemove = nil
+		for _, c := range result.Candidates {
+			name := c.Worktree.Name
+			if err := gitMgr.UnlockAndRemove(c.Worktree, false); err != nil {
+				report.Errors = append(report.Errors, pruneEntry{Name: name, Path: c.Worktree.Path, Error: err.Error()})
+				if !pruneJSON {
+					fmt.Printf("⚠  Failed to remove %s: %v\n", name, err)
+				}
 				continue
 			}
 			metaStore.Remove(name)
-			removed++
-			fmt.Printf("✓ Removed %s\n", name)
+			entry := newPruneEntry(c.Worktree, c.Reasons)
+
+			branchNote := ""
+			if deleteBranches && c.Worktree.Branch != "" {
+				if why := deleteWorktreeBranch(c.Worktree, pruneForceDeleteBranch); why != "" {
+					entry.KeptBranch = why
+					branchNote = fmt.Sprintf(" (kept branch %s: %s)", c.Worktree.Branch, why)
+				} else {
+					entry.DeletedBranch = c.Worktree.Branch
+					deletedBranches++
+					branchNote = fmt.Sprintf(" (deleted branch %s)", c.Worktree.Branch)
+				}
+			}
+
+			report.Removed = append(report.Removed, entry)
+			if !pruneJSON {
+				fmt.Printf("✓ Removed %s%s\n", name, branchNote)
+			}
 		}
 
 		if err := metaStore.Save(); err != nil {
-			fmt.Printf("Warning: failed to update metadata: %v\n", err)
+			report.Errors = append(report.Errors, pruneEntry{Error: fmt.Sprintf("failed to update metadata: %v", err)})
+			if !pruneJSON {
+				fmt.Printf("Warning: failed to update metadata: %v\n", err)
+			}
+		}
+
+		if pruneJSON {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("\n✓ Removed %d worktree(s)", len(report.Removed))
+			if deleteBranches {
+				fmt.Printf(" and %d branch(es)", deletedBranches)
+			}
+			fmt.Println()
 		}
 
-		fmt.Printf("\n✓ Removed %d worktree(s)\n", removed)
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("failed to remove %d worktree(s)", len(report.Errors))
+		}
 		return nil
 	},
 }
 
+// pruneConfirmError explains why prune can't ask for confirmation before
+// deleting, or returns nil if it can or doesn't need to
+func pruneConfirmError(stdinIsTerminal bool) error {
+	switch {
+	case pruneDryRun || pruneYes:
+		return nil
+	case pruneJSON:
+		return fmt.Errorf("refusing to delete without confirmation: --json doesn't prompt\nUse --yes to delete or --dry-run to preview")
+	case !stdinIsTerminal:
+		return fmt.Errorf("refusing to delete without confirmation: stdin is not a terminal\nUse --yes to delete or --dry-run to preview")
+	}
+	return nil
+}
+
+// printPruneResult prints candidates and skipped worktrees for humans
+func printPruneResult(policy prune.Policy, result *prune.Result) {
+	if result.Empty() {
+		fmt.Printf("No worktrees found (%s)\n", policy.Describe())
+		return
+	}
+
+	for _, s := range result.Skipped {
+		icon := "⊘"
+		switch {
+		case s.Err != nil:
+			icon = "⚠ "
+		case s.Worktree.Locked:
+			icon = "🔒"
+		}
+		if len(s.Reasons) == 0 {
+			fmt.Printf("%s Skipping %s: %s\n", icon, s.Worktree.Name, s.Why)
+			continue
+		}
+		fmt.Printf("%s Skipping %s: %s (%s)\n", icon, s.Worktree.Name, s.Why, strings.Join(s.Reasons, ", "))
+	}
+
+	if len(result.Candidates) == 0 {
+		fmt.Printf("No worktrees safe to remove (%s)\n", policy.Describe())
+		return
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Println()
+	}
+	if pruneDryRun {
+		fmt.Printf("Would remove (%s):\n\n", policy.Describe())
+	} else {
+		fmt.Printf("Worktrees to remove (%s):\n\n", policy.Describe())
+	}
+	for _, c := range result.Candidates {
+		fmt.Printf("  • %s (%s)\n", c.Worktree.Name, strings.Join(c.Reasons, ", "))
+	}
+}
+
+// pruneReport is the machine-readable output of wtx prune --json
+type pruneReport struct {
+	DryRun      bool         `json:"dry_run"`
+	Policy      string       `json:"policy"`
+	WouldRemove []pruneEntry `json:"would_remove,omitempty"`
+	Removed     []pruneEntry `json:"removed"`
+	Skipped     []pruneEntry `json:"skipped"`
+	Errors      []pruneEntry `json:"errors"`
+}
+
+// pruneEntry describes one worktree in a prune report
+type pruneEntry struct {
+	Name    string   `json:"name,omitempty"`
+	Path    string   `json:"path,omitempty"`
+	Branch  string   `json:"branch,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+	Skipped string   `json:"skipped,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	// DeletedBranch is set when the worktree's branch was deleted, and
+	// KeptBranch explains why it wasn't
+	DeletedBranch string `json:"deleted_branch,omitempty"`
+	KeptBranch    string `json:"kept_branch,omitempty"`
+}
+
+// newPruneReport creates a report listing candidates as would-be removals
+func newPruneReport(policy prune.Policy, result *prune.Result) *pruneReport {
+	report := &pruneReport{
+		DryRun:  pruneDryRun,
+		Policy:  policy.Describe(),
+		Removed: []pruneEntry{},
+		Skipped: []pruneEntry{},
+		Errors:  []pruneEntry{},
+	}
+	for _, c := range result.Candidates {
+		report.WouldRemove = append(report.WouldRemove, newPruneEntry(c.Worktree, c.Reasons))
+	}
+	for _, s := range result.Skipped {
+		entry := newPruneEntry(s.Worktree, s.Reasons)
+		entry.Skipped = s.Why
+		if s.Err != nil {
+			entry.Error = s.Err.Error()
+		}
+		report.Skipped = append(report.Skipped, entry)
+	}
+	return report
+}
+
+func newPruneEntry(wt git.Worktree, reasons []string) pruneEntry {
+	return pruneEntry{
+		Name:    wt.Name,
+		Path:    wt.Path,
+		Branch:  wt.Branch,
+		Reasons: reasons,
+	}
+}
+
+// prunePolicy builds the prune policy from the command line flags
+func prunePolicy(cmd *cobra.Command) (prune.Policy, error) {
+	policy := prune.Policy{
+		Merged:      pruneMerged,
+		Gone:        pruneGone,
+		Detached:    pruneDetached,
+		AllowLocked: pruneAllowLocked,
+	}
+
+	switch {
+	case cmd.Flags().Changed("older-than"):
+		age, err := prune.ParseAge(pruneOlderThan)
+		if err != nil {
+			return policy, err
+		}
+		policy.OlderThan = age
+	case cmd.Flags().Changed("days"):
+		if staleDays <= 0 {
+			return policy, fmt.Errorf("--days must be greater than zero")
+		}
+		policy.OlderThan = time.Duration(staleDays) * 24 * time.Hour
+	case !pruneMerged && !pruneGone && !pruneDetached:
+		// No criteria given: keep the historical behavior, and drop
+		// detached worktrees since they're throwaway checkouts
+		policy.OlderThan = time.Duration(staleDays) * 24 * time.Hour
+		policy.Detached = true
+	}
+
+	return policy, nil
+}
+
 func init() {
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "30d", "Select worktrees not opened for this long (e.g. 30d, 2w, 12h)")
+	pruneCmd.Flags().BoolVar(&pruneMerged, "merged", false, "Select worktrees whose branch is merged into the default branch")
+	pruneCmd.Flags().BoolVar(&pruneGone, "gone", false, "Select worktrees whose upstream branch was deleted")
+	pruneCmd.Flags().BoolVar(&pruneDetached, "detached", false, "Select detached worktrees created with 'wtx add --at'")
 	pruneCmd.Flags().IntVarP(&staleDays, "days", "d", 30, "Number of days to consider a worktree stale")
+	_ = pruneCmd.Flags().MarkDeprecated("days", "use --older-than instead")
+	pruneCmd.Flags().BoolVar(&pruneAllowLocked, "allow-locked", false, "Also prune locked worktrees")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Remove without asking for confirmation")
+	deleteBranchFlags(pruneCmd, &pruneDeleteBranch, &pruneForceDeleteBranch)
+	pruneCmd.Flags().BoolVar(&pruneJSON, "json", false, "Print a machine-readable report (requires --yes or --dry-run)")
 }
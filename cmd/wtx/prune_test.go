@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/darkLord19/wtx/internal/config"
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/metadata"
+)
+
+// setupPruneRepo points the command globals at a new repository whose
+// worktree "done" is on a branch merged into main, so --merged selects it
+func setupPruneRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repoPath := filepath.Join(dir, "repo")
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q")
+	run("symbolic-ref", "HEAD", "refs/heads/main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	run("worktree", "add", "-q", "-b", "done", filepath.Join(dir, "done"), "main")
+
+	repo := &git.Repository{Path: repoPath, GitDir: filepath.Join(repoPath, ".git")}
+	gitMgr = git.NewManager(repo)
+	store, err := metadata.LoadFromGitDir(repo.Path, repo.GitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metaStore = store
+	cfg = config.Default()
+
+	// Reset prune's flags after each test
+	t.Cleanup(func() {
+		pruneMerged, pruneDryRun, pruneYes, pruneJSON = false, false, false, false
+	})
+
+	// Tests never have a terminal to confirm on
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = devNull
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		devNull.Close()
+	})
+
+	return filepath.Join(dir, "done")
+}
+
+// runPrune runs wtx prune and returns its output
+func runPrune(t *testing.T) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := pruneCmd.RunE(pruneCmd, nil)
+	os.Stdout = stdout
+	w.Close()
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), runErr
+}
+
+func TestPruneRefusesWithoutTerminal(t *testing.T) {
+	done := setupPruneRepo(t)
+	pruneMerged = true
+
+	_, err := runPrune(t)
+	if err == nil || !strings.Contains(err.Error(), "stdin is not a terminal") {
+		t.Fatalf("Expected refusal without a terminal, got %v", err)
+	}
+	if _, err := os.Stat(done); err != nil {
+		t.Errorf("Expected worktree to be kept: %v", err)
+	}
+
+	// --json has no prompt even on a terminal
+	pruneJSON = true
+	if err := pruneConfirmError(true); err == nil || !strings.Contains(err.Error(), "--json") {
+		t.Errorf("Expected --json refusal, got %v", err)
+	}
+	pruneJSON = false
+	if err := pruneConfirmError(true); err != nil {
+		t.Errorf("Expected a terminal to be able to confirm, got %v", err)
+	}
+}
+
+func TestPruneNothingToRemove(t *testing.T) {
+	setupPruneRepo(t)
+	pruneMerged, pruneYes = true, true
+	if _, err := runPrune(t); err != nil {
+		t.Fatalf("First prune failed: %v", err)
+	}
+
+	// Unattended runs with nothing to prune succeed
+	pruneYes = false
+	output, err := runPrune(t)
+	if err != nil {
+		t.Fatalf("Expected no error with nothing to prune, got %v", err)
+	}
+	if !strings.Contains(output, "No worktrees found") {
+		t.Errorf("Unexpected output: %s", output)
+	}
+}
+
+func TestPruneJSONReport(t *testing.T) {
+	done := setupPruneRepo(t)
+	pruneMerged, pruneJSON, pruneDryRun = true, true, true
+
+	output, err := runPrune(t)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	var report pruneReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	if !report.DryRun || len(report.WouldRemove) != 1 || report.WouldRemove[0].Name != "done" ||
+		report.WouldRemove[0].Branch != "done" || len(report.WouldRemove[0].Reasons) == 0 {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	if report.Removed == nil || report.Skipped == nil || report.Errors == nil {
+		t.Errorf("Expected empty lists rather than null: %s", output)
+	}
+	if _, err := os.Stat(done); err != nil {
+		t.Errorf("Expected --dry-run to keep the worktree: %v", err)
+	}
+
+	pruneDryRun, pruneYes = false, true
+	output, err = runPrune(t)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	report = pruneReport{}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	if report.DryRun || len(report.Removed) != 1 || report.Removed[0].Name != "done" || len(report.WouldRemove) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if _, err := os.Stat(done); !os.IsNotExist(err) {
+		t.Errorf("Expected worktree to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var (
+	repairYes bool
+)
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Find and fix broken worktrees",
+	Long: `Find worktrees whose directory is missing or whose gitdir link is broken.
+
+Broken links (e.g. after moving the repository by hand) are fixed with
+'git worktree repair'. Worktrees whose directory is gone can be pruned with
+'git worktree prune'. wtx metadata is reconciled with what is left.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		worktrees, err := gitMgr.List()
+		if err != nil {
+			return err
+		}
+
+		var broken, missing []git.Worktree
+		for _, wt := range worktrees {
+			switch gitMgr.Diagnose(wt) {
+			case git.ProblemBrokenLink:
+				broken = append(broken, wt)
+			case git.ProblemMissing:
+				missing = append(missing, wt)
+			}
+		}
+
+		if len(broken) == 0 && len(missing) == 0 {
+			fmt.Println("✓ All worktrees are healthy")
+		}
+
+		// Re-link worktrees git can still find on disk
+		if len(broken) > 0 {
+			fmt.Printf("Repairing %d worktree(s) with broken links:\n", len(broken))
+			paths := make([]string, 0, len(broken))
+			for _, wt := range broken {
+				fmt.Printf("  - %s (%s)\n", wt.Name, wt.Path)
+				paths = append(paths, wt.Path)
+			}
+
+			if err := gitMgr.Repair(paths...); err != nil {
+				fmt.Printf("⚠  %v\n", err)
+			}
+
+			for _, wt := range broken {
+				if gitMgr.Diagnose(wt) == git.ProblemNone {
+					fmt.Printf("✓ Repaired: %s\n", wt.Name)
+				} else {
+					fmt.Printf("✗ Could not repair: %s\n", wt.Name)
+				}
+			}
+			fmt.Println()
+		}
+
+		// Missing directories can only be forgotten
+		if len(missing) > 0 {
+			fmt.Printf("Found %d worktree(s) whose directory is missing:\n", len(missing))
+			for _, wt := range missing {
+				line := fmt.Sprintf("  - %s (%s)", wt.Name, wt.Path)
+				if wt.Locked {
+					line += " 🔒 locked, will not be pruned"
+				}
+				fmt.Println(line)
+			}
+
+			prune := repairYes
+			if !prune {
+				fmt.Print("\nPrune them from git? [y/N]: ")
+				var response string
+				_, _ = fmt.Scanln(&response)
+				prune = response == "y" || response == "Y"
+			}
+
+			if prune {
+				if err := gitMgr.Prune(); err != nil {
+					return err
+				}
+				fmt.Println("✓ Pruned missing worktrees")
+			} else {
+				fmt.Println("Skipped pruning")
+			}
+			fmt.Println()
+		}
+
+		// Bring metadata in line with what git knows about now
+		worktrees, err = gitMgr.List()
+		if err != nil {
+			return err
+		}
+		current := make(map[string]string, len(worktrees))
+		for _, wt := range worktrees {
+			current[wt.Name] = wt.Path
+		}
+
+		removed, updated := metaStore.Reconcile(current)
+		if len(removed) == 0 && len(updated) == 0 {
+			return nil
+		}
+
+		for _, name := range updated {
+			fmt.Printf("✓ Updated metadata for: %s\n", name)
+		}
+		for _, name := range removed {
+			fmt.Printf("✓ Removed metadata for: %s\n", name)
+		}
+		if err := metaStore.Save(); err != nil {
+			fmt.Printf("Warning: failed to update metadata: %v\n", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	repairCmd.Flags().BoolVarP(&repairYes, "yes", "y", false, "Prune missing worktrees without asking")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/metadata"
+)
+
+var (
+	reviewRemote string
+	reviewBase   string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review <number>",
+	Short: "Check out a pull request in a new worktree",
+	Long: `Fetch a pull request into a local branch and create a worktree for it.
+
+The head is fetched from refs/pull/<number>/head (GitHub) or
+refs/merge-requests/<number>/head (GitLab) into the branch pr-<number>, and
+the worktree is named pr-<number>. A diffstat against the base branch is
+printed so you know what you're about to review.`,
+	Example: `  wtx review 456
+  wtx review 456 --remote upstream --base develop`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || number <= 0 {
+			return fmt.Errorf("invalid pull request number '%s'", args[0])
+		}
+
+		name := git.PullRequestBranch(number)
+		branch := name
+
+		worktrees, err := gitMgr.List()
+		if err != nil {
+			return err
+		}
+		for _, wt := range worktrees {
+			if wt.Branch == branch {
+				return fmt.Errorf("pull request #%d is already checked out in '%s'\nUse 'wtx open %s', or 'git pull' in it to update", number, wt.Name, wt.Name)
+			}
+		}
+
+		remote, err := gitMgr.ReviewRemote(reviewRemote)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Fetching pull request #%d from %s...\n", number, remote)
+		ref, err := gitMgr.FetchPullRequest(remote, number, branch)
+		if err != nil {
+			return err
+		}
+
+		result, err := gitMgr.AddWithOptions(git.AddOptions{Name: name, Branch: branch})
+		if err != nil {
+			return err
+		}
+		path := result.Path
+
+		base := reviewBase
+		if base == "" {
+			base = gitMgr.DefaultBranch()
+		}
+
+		fmt.Printf("✓ Created worktree: %s\n", name)
+		fmt.Printf("  Path: %s\n", path)
+		fmt.Printf("  Branch: %s (from %s %s)\n", branch, remote, ref)
+		fmt.Printf("  Base: %s\n", base)
+		printIncludes(result.Included)
+		if result.IncludeErr != nil {
+			fmt.Printf("  ✗ %v\n", result.IncludeErr)
+		}
+
+		if stat, err := gitMgr.DiffStat(base, branch); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+		} else if stat != "" {
+			fmt.Println()
+			fmt.Println(stat)
+		}
+
+		printPrepared(result)
+
+		meta := &metadata.WorktreeMetadata{
+			Name:       name,
+			Path:       path,
+			Branch:     branch,
+			CreatedAt:  time.Now(),
+			LastOpened: time.Now(),
+			PRNumber:   number,
+			PRBase:     base,
+		}
+		metaStore.Add(meta)
+		if err := metaStore.Save(); err != nil {
+			fmt.Printf("Warning: failed to save metadata: %v\n", err)
+		}
+
+		return promptOpen(path)
+	},
+}
+
+func init() {
+	reviewCmd.Flags().StringVarP(&reviewRemote, "remote", "r", "", "Remote to fetch the pull request from (default: preferred_remote, then origin)")
+	reviewCmd.Flags().StringVarP(&reviewBase, "base", "b", "", "Branch the pull request targets (default: the repository's default branch)")
+}
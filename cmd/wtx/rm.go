@@ -7,35 +7,35 @@ import (
 )
 
 var (
-	forceRemove bool
+	forceRemove       bool
+	removeAllowLocked bool
+
+	removeDeleteBranch      bool
+	removeForceDeleteBranch bool
 )
 
 var rmCmd = &cobra.Command{
 	Use:   "rm <name>",
 	Short: "Remove a worktree",
-	Long:  "Safely remove a worktree. Prompts for confirmation if worktree has uncommitted changes.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Safely remove a worktree. Prompts for confirmation if worktree has uncommitted changes.
+
+With --delete-branch (or delete_branch in the config) the worktree's branch is
+deleted too, but only if it is merged into the default branch or fully pushed
+to its upstream. -D deletes it regardless.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
 		// Find the worktree
-		worktrees, err := gitMgr.List()
+		wt, err := findWorktree(name)
 		if err != nil {
 			return err
 		}
+		targetPath := wt.Path
 
-		var targetPath string
-		var found bool
-		for _, wt := range worktrees {
-			if wt.Name == name {
-				targetPath = wt.Path
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return fmt.Errorf("worktree '%s' not found", name)
+		// Locked worktrees are protected unless explicitly overridden
+		if wt.Locked && !removeAllowLocked {
+			return lockedError(wt)
 		}
 
 		// Check if clean
@@ -67,7 +67,7 @@ var rmCmd = &cobra.Command{
 
 		// Remove worktree
 		fmt.Printf("Removing worktree '%s'...\n", name)
-		if err := gitMgr.Remove(name, forceRemove); err != nil {
+		if err := gitMgr.UnlockAndRemove(*wt, forceRemove); err != nil {
 			return err
 		}
 
@@ -78,10 +78,20 @@ var rmCmd = &cobra.Command{
 		}
 
 		fmt.Printf("✓ Removed worktree: %s\n", name)
+
+		if wt.Branch != "" && wantDeleteBranch(cmd, removeDeleteBranch, removeForceDeleteBranch) {
+			if why := deleteWorktreeBranch(*wt, removeForceDeleteBranch); why != "" {
+				fmt.Printf("⊘ Kept branch %s: %s\n", wt.Branch, why)
+			} else {
+				fmt.Printf("✓ Deleted branch: %s\n", wt.Branch)
+			}
+		}
 		return nil
 	},
 }
 
 func init() {
 	rmCmd.Flags().BoolVarP(&forceRemove, "force", "f", false, "Force removal even with uncommitted changes")
+	rmCmd.Flags().BoolVar(&removeAllowLocked, "allow-locked", false, "Remove the worktree even if it is locked")
+	deleteBranchFlags(rmCmd, &removeDeleteBranch, &removeForceDeleteBranch)
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var sparseCmd = &cobra.Command{
+	Use:   "sparse [name] [add|rm <path>...]",
+	Short: "Show or adjust a worktree's sparse checkout",
+	Long: `Show or adjust the cone-mode sparse checkout of a worktree.
+
+With no arguments, list the available sparse profiles. With a worktree name,
+show its profile and checked-out directories. 'add' and 'rm' change which
+directories are checked out.
+
+Profiles are defined in sparse_profiles in the config file or in a .wtxsparse
+file at the repository root, and applied with 'wtx add --sparse <profile>'.`,
+	Example: `  wtx sparse
+  wtx sparse feature-auth
+  wtx sparse feature-auth add services/auth
+  wtx sparse feature-auth rm docs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return listSparseProfiles()
+		}
+
+		name := args[0]
+		wt, err := findWorktree(name)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			return showSparse(wt)
+		}
+
+		action, paths := args[1], args[2:]
+		if len(paths) == 0 {
+			return fmt.Errorf("usage: wtx sparse %s %s <path>...", name, action)
+		}
+
+		switch action {
+		case "add":
+			current, err := gitMgr.SparsePaths(wt.Path)
+			if err != nil {
+				return err
+			}
+			// Turning a full checkout sparse needs set rather than add
+			if current == nil {
+				err = gitMgr.SetSparsePaths(wt.Path, paths)
+			} else {
+				err = gitMgr.AddSparsePaths(wt.Path, paths)
+			}
+			if err != nil {
+				return err
+			}
+		case "rm", "remove":
+			if err := gitMgr.RemoveSparsePaths(wt.Path, paths); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown action '%s' (use add or rm)", action)
+		}
+
+		return showSparse(wt)
+	},
+}
+
+// listSparseProfiles prints the profiles available for wtx add --sparse
+func listSparseProfiles() error {
+	profiles, err := gitMgr.SparseProfiles(cfg.SparseProfiles)
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		fmt.Printf("No sparse profiles defined. Add sparse_profiles to the config or a %s file to the repository.\n", git.SparseProfileFile)
+		return nil
+	}
+
+	fmt.Println("Sparse profiles:")
+	for _, name := range git.ProfileNames(profiles) {
+		fmt.Printf("  %-20s %s\n", name, strings.Join(profiles[name], " "))
+	}
+	return nil
+}
+
+// showSparse prints a worktree's sparse profile and checked-out directories
+func showSparse(wt *git.Worktree) error {
+	paths, err := gitMgr.SparsePaths(wt.Path)
+	if err != nil {
+		return err
+	}
+
+	if paths == nil {
+		fmt.Printf("%s is a full checkout\n", wt.Name)
+		return nil
+	}
+
+	if meta, ok := metaStore.Get(wt.Name); ok && meta.SparseProfile != "" {
+		fmt.Printf("%s (profile: %s)\n", wt.Name, meta.SparseProfile)
+	} else {
+		fmt.Println(wt.Name)
+	}
+	if len(paths) == 0 {
+		fmt.Println("  (top-level files only)")
+	}
+	for _, p := range paths {
+		fmt.Printf("  %s\n", p)
+	}
+	return nil
+}
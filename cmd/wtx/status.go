@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
 )
 
 var statusCmd = &cobra.Command{
@@ -15,36 +19,11 @@ var statusCmd = &cobra.Command{
 		name := args[0]
 
 		// Find the worktree
-		worktrees, err := gitMgr.List()
+		target, err := findWorktree(name)
 		if err != nil {
 			return err
 		}
 
-		var target *struct {
-			Path   string
-			Branch string
-			IsMain bool
-		}
-
-		for _, wt := range worktrees {
-			if wt.Name == name {
-				target = &struct {
-					Path   string
-					Branch string
-					IsMain bool
-				}{
-					Path:   wt.Path,
-					Branch: wt.Branch,
-					IsMain: wt.IsMain,
-				}
-				break
-			}
-		}
-
-		if target == nil {
-			return fmt.Errorf("worktree '%s' not found", name)
-		}
-
 		// Get status
 		status, err := gitMgr.GetStatus(target.Path)
 		if err != nil {
@@ -58,7 +37,7 @@ var statusCmd = &cobra.Command{
 		fmt.Printf("\nWorktree: %s\n", name)
 		fmt.Println("─────────────────────────────────────")
 		fmt.Printf("Path:     %s\n", target.Path)
-		fmt.Printf("Branch:   %s\n", target.Branch)
+		fmt.Printf("Branch:   %s\n", branchLabel(*target))
 
 		if target.IsMain {
 			fmt.Println("Type:     Main worktree ⭐")
@@ -66,12 +45,54 @@ var statusCmd = &cobra.Command{
 			fmt.Println("Type:     Linked worktree")
 		}
 
+		base := gitMgr.DefaultBranch()
+		if commits, err := gitMgr.CommitInfos([]git.Worktree{*target}, base); err == nil {
+			if info, ok := commits[target.Head]; ok {
+				fmt.Printf("Commit:   %.7s %s\n", info.Hash, info.Subject)
+				fmt.Printf("          %s, %s\n", info.Author, info.Age(time.Now()))
+				if info.Base != "" && !target.IsMain {
+					fmt.Printf("          %d commit(s) since %s\n", info.SinceBase, info.Base)
+				}
+			}
+		}
+
 		fmt.Println()
 		fmt.Println("Git Status:")
+		if status.Operation != git.OperationNone {
+			op := string(status.Operation)
+			fmt.Printf("  ⚠ %s in progress\n", strings.ToUpper(op[:1])+op[1:])
+		}
+		if status.Detached && (meta == nil || meta.DetachedAt == "") {
+			fmt.Println("  ⚠ Detached HEAD")
+		}
+
 		if status.Clean {
 			fmt.Println("  ● Working tree clean")
 		} else {
 			fmt.Println("  ✗ Uncommitted changes")
+			if status.Staged > 0 {
+				fmt.Printf("    %d staged\n", status.Staged)
+			}
+			if status.Unstaged > 0 {
+				fmt.Printf("    %d unstaged\n", status.Unstaged)
+			}
+			if status.Untracked > 0 {
+				fmt.Printf("    %d untracked\n", status.Untracked)
+			}
+			if status.Conflicted > 0 {
+				fmt.Printf("    %d conflicted\n", status.Conflicted)
+			}
+		}
+
+		switch {
+		case status.Upstream == "":
+			if !status.Detached {
+				fmt.Println("  No upstream configured")
+			}
+		case status.UpstreamGone:
+			fmt.Printf("  ⊘ Upstream %s is gone\n", status.Upstream)
+		default:
+			fmt.Printf("  Upstream: %s\n", status.Upstream)
 		}
 
 		if status.Ahead > 0 {
@@ -81,12 +102,20 @@ var statusCmd = &cobra.Command{
 			fmt.Printf("  ↓ %d commit(s) behind upstream\n", status.Behind)
 		}
 
+		if git.HasSubmodules(target.Path) {
+			printSubmodules(target.Path)
+		}
+
 		if meta != nil {
 			fmt.Println()
 			fmt.Println("Metadata:")
 			fmt.Printf("  Created:     %s\n", meta.CreatedAt.Format("2006-01-02 15:04"))
 			fmt.Printf("  Last opened: %s\n", meta.LastOpened.Format("2006-01-02 15:04"))
 
+			if meta.PRNumber > 0 {
+				fmt.Printf("  Reviewing:   #%d (base %s)\n", meta.PRNumber, meta.PRBase)
+			}
+
 			if meta.DevCommand != "" {
 				fmt.Printf("  Dev command: %s\n", meta.DevCommand)
 			}
@@ -100,3 +129,30 @@ var statusCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// printSubmodules lists submodules that need attention in a worktree
+func printSubmodules(path string) {
+	fmt.Println()
+	fmt.Println("Submodules:")
+
+	submodules, err := gitMgr.Submodules(path)
+	if err != nil {
+		fmt.Printf("  ✗ %v\n", err)
+		return
+	}
+
+	stale := 0
+	for _, sm := range submodules {
+		if sm.State == git.SubmoduleUpToDate {
+			continue
+		}
+		stale++
+		fmt.Printf("  ✗ %s (%s)\n", sm.Path, sm.State)
+	}
+
+	if stale == 0 {
+		fmt.Printf("  ● %d submodule(s) up to date\n", len(submodules))
+	} else {
+		fmt.Println("  Run 'git submodule update --init --recursive' to fix")
+	}
+}
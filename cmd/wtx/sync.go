@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+var (
+	syncRebase bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Fetch and update all worktrees",
+	Long: `Fetch all remotes once, then fast-forward every clean worktree that is
+behind its upstream. Dirty worktrees are skipped. With --rebase, clean
+worktrees that have diverged are rebased onto their upstream; a rebase that
+hits conflicts is aborted and the worktree is left untouched.
+
+Exits with an error if any worktree conflicted or failed to update.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		worktrees, err := gitMgr.List()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Fetching all remotes...")
+		if err := gitMgr.Fetch(); err != nil {
+			return err
+		}
+
+		results := gitMgr.Sync(worktrees, syncRebase)
+
+		fmt.Printf("\n%-20s %-30s %-12s %s\n", "NAME", "BRANCH", "RESULT", "DETAIL")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────────────────────")
+
+		counts := make(map[git.SyncOutcome]int)
+		for _, r := range results {
+			counts[r.Outcome]++
+
+			fmt.Printf("%-20s %-30s %s %-10s %s\n",
+				r.Worktree.Name,
+				branchLabel(r.Worktree),
+				syncSymbol(r.Outcome),
+				r.Outcome,
+				r.Detail,
+			)
+		}
+
+		fmt.Printf("\n%d updated, %d up-to-date, %d skipped", counts[git.SyncUpdated], counts[git.SyncUpToDate], counts[git.SyncSkipped])
+		if n := counts[git.SyncConflict]; n > 0 {
+			fmt.Printf(", %d conflict", n)
+		}
+		if n := counts[git.SyncFailed]; n > 0 {
+			fmt.Printf(", %d failed", n)
+		}
+		fmt.Println()
+
+		// Scripts and CI need to notice worktrees that weren't synced
+		if n := counts[git.SyncConflict] + counts[git.SyncFailed]; n > 0 {
+			// The table already explains what went wrong; usage wouldn't help
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to sync %d worktree(s)", n)
+		}
+		return nil
+	},
+}
+
+// syncSymbol returns the indicator shown next to a sync outcome
+func syncSymbol(outcome git.SyncOutcome) string {
+	switch outcome {
+	case git.SyncUpdated:
+		return "↓"
+	case git.SyncUpToDate:
+		return "●"
+	case git.SyncConflict, git.SyncFailed:
+		return "✗"
+	default:
+		return "⊘"
+	}
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncRebase, "rebase", false, "Rebase clean worktrees that have diverged from their upstream")
+}
@@ -2,20 +2,30 @@ package config
 
 // Config holds user configuration
 type Config struct {
-	Editor         string            `mapstructure:"editor"`
-	ReuseWindow    bool              `mapstructure:"reuse_window"`
-	WorktreeDir    string            `mapstructure:"worktree_dir"`
-	AutoStartDev   bool              `mapstructure:"auto_start_dev"`
-	CustomCommands map[string]string `mapstructure:"custom_commands"`
+	Editor          string            `mapstructure:"editor"`
+	ReuseWindow     bool              `mapstructure:"reuse_window"`
+	WorktreeDir     string            `mapstructure:"worktree_dir"`
+	AutoStartDev    bool              `mapstructure:"auto_start_dev"`
+	PreferredRemote string            `mapstructure:"preferred_remote"`
+	StatusWorkers   int               `mapstructure:"status_workers"`
+	DeleteBranch    bool              `mapstructure:"delete_branch"`
+	CustomCommands  map[string]string `mapstructure:"custom_commands"`
+	// SparseProfiles maps a profile name to cone-mode directories for
+	// wtx add --sparse
+	SparseProfiles map[string][]string `mapstructure:"sparse_profiles"`
 }
 
 // Default returns the default configuration
 func Default() *Config {
 	return &Config{
-		Editor:         "", // Auto-detect
-		ReuseWindow:    true,
-		WorktreeDir:    "../worktrees",
-		AutoStartDev:   false,
-		CustomCommands: make(map[string]string),
+		Editor:          "", // Auto-detect
+		ReuseWindow:     true,
+		WorktreeDir:     "../worktrees",
+		AutoStartDev:    false,
+		PreferredRemote: "",
+		StatusWorkers:   10,
+		DeleteBranch:    false,
+		CustomCommands:  make(map[string]string),
+		SparseProfiles:  make(map[string][]string),
 	}
 }
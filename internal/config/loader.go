@@ -45,6 +45,9 @@ func LoadWithFirstRunCheck() (*LoadResult, error) {
 	v.SetDefault("reuse_window", cfg.ReuseWindow)
 	v.SetDefault("worktree_dir", cfg.WorktreeDir)
 	v.SetDefault("auto_start_dev", cfg.AutoStartDev)
+	v.SetDefault("preferred_remote", cfg.PreferredRemote)
+	v.SetDefault("status_workers", cfg.StatusWorkers)
+	v.SetDefault("delete_branch", cfg.DeleteBranch)
 
 	// Read config
 	if err := v.ReadInConfig(); err != nil {
@@ -94,7 +97,11 @@ func (c *Config) Save() error {
 	v.Set("reuse_window", c.ReuseWindow)
 	v.Set("worktree_dir", c.WorktreeDir)
 	v.Set("auto_start_dev", c.AutoStartDev)
+	v.Set("preferred_remote", c.PreferredRemote)
+	v.Set("status_workers", c.StatusWorkers)
+	v.Set("delete_branch", c.DeleteBranch)
 	v.Set("custom_commands", c.CustomCommands)
+	v.Set("sparse_profiles", c.SparseProfiles)
 
 	// We use WriteConfigAs to ensure we write to the specific file,
 	// creating it if it doesn't exist or overwriting if it does.
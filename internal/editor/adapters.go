@@ -32,6 +32,10 @@ func (e *VSCodeEditor) Open(path string, reuseWindow bool) error {
 	return cmd.Start()
 }
 
+func (e *VSCodeEditor) OpenDiff(left, right string) error {
+	return execCommand("code", "--diff", left, right).Start()
+}
+
 // CursorEditor implements the Editor interface for Cursor
 type CursorEditor struct{}
 
@@ -54,6 +58,10 @@ func (e *CursorEditor) Open(path string, reuseWindow bool) error {
 	return cmd.Start()
 }
 
+func (e *CursorEditor) OpenDiff(left, right string) error {
+	return execCommand("cursor", "--diff", left, right).Start()
+}
+
 // VSCodiumEditor implements the Editor interface for VSCodium
 type VSCodiumEditor struct{}
 
@@ -76,6 +84,10 @@ func (e *VSCodiumEditor) Open(path string, reuseWindow bool) error {
 	return cmd.Start()
 }
 
+func (e *VSCodiumEditor) OpenDiff(left, right string) error {
+	return execCommand("codium", "--diff", left, right).Start()
+}
+
 // NeovimEditor implements the Editor interface for Neovim
 type NeovimEditor struct{}
 
@@ -96,6 +108,14 @@ func (e *NeovimEditor) Open(path string, reuseWindow bool) error {
 	return cmd.Run()
 }
 
+func (e *NeovimEditor) OpenDiff(left, right string) error {
+	cmd := execCommand("nvim", "-d", left, right)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // VimEditor implements the Editor interface for Vim
 type VimEditor struct{}
 
@@ -116,6 +136,14 @@ func (e *VimEditor) Open(path string, reuseWindow bool) error {
 	return cmd.Run()
 }
 
+func (e *VimEditor) OpenDiff(left, right string) error {
+	cmd := execCommand("vim", "-d", left, right)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // TerminalEditor is a fallback that just prints the path
 type TerminalEditor struct{}
 
@@ -9,6 +9,11 @@ type Editor interface {
 	Open(path string, reuseWindow bool) error
 }
 
+// DiffOpener is implemented by editors with a side-by-side diff view
+type DiffOpener interface {
+	OpenDiff(left, right string) error
+}
+
 // EditorType represents different editor types
 type EditorType string
 
@@ -112,6 +112,31 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenDiff(t *testing.T) {
+	defer func() {
+		execCommand = exec.Command
+	}()
+	execCommand = mockExecCommand
+
+	for _, edType := range []EditorType{VSCode, Cursor, VSCodium, Neovim, Vim} {
+		t.Run(string(edType), func(t *testing.T) {
+			editor, _ := New(edType)
+			differ, ok := editor.(DiffOpener)
+			if !ok {
+				t.Fatalf("%s does not implement DiffOpener", edType)
+			}
+			if err := differ.OpenDiff("a.go", "b.go"); err != nil {
+				t.Errorf("OpenDiff() failed for %s: %v", edType, err)
+			}
+		})
+	}
+
+	terminal, _ := New(Terminal)
+	if _, ok := terminal.(DiffOpener); ok {
+		t.Error("Terminal should not implement DiffOpener")
+	}
+}
+
 func TestDetector(t *testing.T) {
 	defer func() {
 		execLookPath = exec.LookPath
@@ -53,3 +53,76 @@ func BenchmarkGetStatusesParallel(b *testing.B) {
 		mgr.GetStatuses(worktrees)
 	}
 }
+
+// BenchmarkList100 compares listing 100 worktrees via git worktree list and
+// by reading the administrative files directly
+func BenchmarkList100(b *testing.B) {
+	repoPath, cleanup, err := setupTestRepo(nil, 100)
+	if err != nil {
+		b.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	b.Run("exec", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := mgr.listExec(); err != nil {
+				b.Fatalf("listExec failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("native", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := mgr.listNative(); err != nil {
+				b.Fatalf("listNative failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkAheadBehind100 compares one rev-list per branch with a single
+// batched for-each-ref call for 100 tracking branches
+func BenchmarkAheadBehind100(b *testing.B) {
+	repoPath, cleanup, err := setupTestRepo(nil, 100)
+	if err != nil {
+		b.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	worktrees, err := mgr.List()
+	if err != nil {
+		b.Fatalf("Failed to list worktrees: %v", err)
+	}
+
+	var branches []string
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			continue
+		}
+		if _, err := mgr.git("branch", "--set-upstream-to=main", wt.Branch); err != nil {
+			b.Fatalf("Failed to set upstream: %v", err)
+		}
+		branches = append(branches, wt.Branch)
+	}
+
+	b.Run("rev-list", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, branch := range branches {
+				if _, err := mgr.git("rev-list", "--left-right", "--count", branch+"...main"); err != nil {
+					b.Fatalf("rev-list failed: %v", err)
+				}
+			}
+		}
+	})
+
+	b.Run("for-each-ref", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := mgr.BranchTracking(); err != nil {
+				b.Fatalf("BranchTracking failed: %v", err)
+			}
+		}
+	})
+}
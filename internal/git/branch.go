@@ -0,0 +1,311 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultBranchKey is the git config key holding a per-repo default branch
+const defaultBranchKey = "wtx.defaultBranch"
+
+// fallbackBranch is used when nothing better can be detected
+const fallbackBranch = "main"
+
+// DefaultBranch returns the branch new worktrees are based on when no base is
+// given. It checks, in order: the wtx.defaultBranch git config, origin/HEAD,
+// the branch checked out in the main worktree, and finally "main".
+func (m *Manager) DefaultBranch() string {
+	if branch := m.DefaultBranchOverride(); branch != "" {
+		return branch
+	}
+
+	if branch := m.remoteDefaultBranch("origin"); branch != "" {
+		return branch
+	}
+
+	// The main worktree's HEAD is the repository HEAD
+	if output, err := m.git("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		branch := strings.TrimSpace(string(output))
+		// Bare clones can point HEAD at a branch that was never created
+		if m.localBranchExists(branch) {
+			return branch
+		}
+	}
+
+	return fallbackBranch
+}
+
+// DefaultBranchOverride returns the configured wtx.defaultBranch, if any
+func (m *Manager) DefaultBranchOverride() string {
+	output, err := m.git("config", "--get", defaultBranchKey)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// SetDefaultBranch stores a per-repo default branch in the repository's git
+// config. An empty branch removes the override.
+func (m *Manager) SetDefaultBranch(branch string) error {
+	args := []string{"config", "--local", defaultBranchKey, branch}
+	if branch == "" {
+		args = []string{"config", "--local", "--unset", defaultBranchKey}
+	}
+
+	if _, err := m.git(args...); err != nil {
+		// Exit code 5 means the key wasn't set, which is fine when unsetting
+		if branch == "" && exitCode(err) == 5 {
+			return nil
+		}
+		return fmt.Errorf("failed to set default branch: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// remoteDefaultBranch resolves <remote>/HEAD. It returns the local branch of
+// the same name if one exists, otherwise the remote-tracking branch.
+func (m *Manager) remoteDefaultBranch(remote string) string {
+	output, err := m.git("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return ""
+	}
+
+	remoteBranch := strings.TrimSpace(string(output))
+	branch := strings.TrimPrefix(remoteBranch, remote+"/")
+	if m.localBranchExists(branch) {
+		return branch
+	}
+	return remoteBranch
+}
+
+// localBranchExists checks if a local branch exists
+func (m *Manager) localBranchExists(branch string) bool {
+	if branch == "" {
+		return false
+	}
+	_, err := m.git("show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// Remotes returns the names of the configured remotes
+func (m *Manager) Remotes() ([]string, error) {
+	output, err := m.git("remote")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// findRemoteBranch returns the remote to take branch from, or "" if no remote
+// has it. If remote is given only that remote is considered. When several
+// remotes have the branch the preferred remote wins, otherwise an
+// AmbiguousBranchError is returned.
+func (m *Manager) findRemoteBranch(branch, remote string) (string, error) {
+	if remote != "" {
+		if !m.remoteBranchExists(remote, branch) {
+			return "", fmt.Errorf("branch '%s' not found on remote '%s' (try 'git fetch %s')", branch, remote, remote)
+		}
+		return remote, nil
+	}
+
+	remotes, err := m.Remotes()
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, r := range remotes {
+		if m.remoteBranchExists(r, branch) {
+			matches = append(matches, r)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+
+	for _, r := range matches {
+		if r == m.preferredRemote {
+			return r, nil
+		}
+	}
+	return "", &AmbiguousBranchError{Branch: branch, Remotes: matches}
+}
+
+// remoteBranchExists checks if a remote-tracking branch exists
+func (m *Manager) remoteBranchExists(remote, branch string) bool {
+	_, err := m.git("show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
+	return err == nil
+}
+
+// IsMerged reports whether branch has been merged into base, i.e. its tip is
+// reachable from base
+func (m *Manager) IsMerged(branch, base string) (bool, error) {
+	if _, err := m.git("merge-base", "--is-ancestor", branch, base); err != nil {
+		if exitCode(err) == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check if %s is merged: %w", branch, err)
+	}
+	return true, nil
+}
+
+// UnsafeBranchError is returned when deleting a branch could lose commits
+type UnsafeBranchError struct {
+	Branch string
+	Reason string
+}
+
+func (e *UnsafeBranchError) Error() string {
+	return fmt.Sprintf("branch '%s' %s", e.Branch, e.Reason)
+}
+
+// DeleteBranch deletes a local branch. Unless force is set, the branch must be
+// merged into base or fully pushed to its upstream; otherwise an
+// UnsafeBranchError explains why it was kept. The base branch itself is never
+// deleted.
+func (m *Manager) DeleteBranch(branch, base string, force bool) error {
+	if branch == base {
+		return fmt.Errorf("refusing to delete the base branch '%s'", base)
+	}
+
+	if !force {
+		reason, err := m.unmergedReason(branch, base)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			return &UnsafeBranchError{Branch: branch, Reason: reason}
+		}
+	}
+
+	// Safety was checked above against base, which git branch -d doesn't know
+	if _, err := m.git("branch", "-D", branch); err != nil {
+		return fmt.Errorf("failed to delete branch: %s", errOutput(err))
+	}
+	return nil
+}
+
+// unmergedReason returns why branch has work that exists nowhere else, or ""
+// if it is merged into base or its upstream has every commit
+func (m *Manager) unmergedReason(branch, base string) (string, error) {
+	merged, err := m.IsMerged(branch, base)
+	if err != nil {
+		return "", err
+	}
+	if merged {
+		return "", nil
+	}
+
+	output, err := m.git("rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil {
+		// An upstream that is configured but doesn't resolve was deleted
+		if _, err := m.git("config", "--get", "branch."+branch+".merge"); err == nil {
+			return fmt.Sprintf("isn't merged into %s and its upstream is gone", base), nil
+		}
+		return fmt.Sprintf("isn't merged into %s and has no upstream", base), nil
+	}
+	upstream := strings.TrimSpace(string(output))
+
+	output, err = m.git("rev-list", "--count", upstream+".."+branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to count unpushed commits: %s", errOutput(err))
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	if count > 0 {
+		return fmt.Sprintf("has %d commit(s) not merged into %s or pushed to %s", count, base, upstream), nil
+	}
+	return "", nil
+}
+
+// UnpushedCount returns the number of commits on branch that are neither on
+// any remote nor in base
+func (m *Manager) UnpushedCount(branch, base string) (int, error) {
+	output, err := m.git("rev-list", "--count", branch, "--not", "--remotes", base)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// UnreferencedCount returns the number of commits reachable from rev that
+// are on no local branch, tag or remote branch, i.e. that would be lost
+// if a detached worktree at rev were removed
+func (m *Manager) UnreferencedCount(rev string) (int, error) {
+	output, err := m.git("rev-list", "--count", rev, "--not", "--branches", "--tags", "--remotes")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unreferenced commits: %w", err)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// Tracking describes how a local branch relates to its upstream
+type Tracking struct {
+	Upstream string
+	Ahead    int
+	Behind   int
+	// Gone is set when the upstream branch no longer exists
+	Gone bool
+}
+
+// BranchTracking returns ahead/behind counts for every local branch with an
+// upstream, keyed by branch name, using a single git for-each-ref call
+func (m *Manager) BranchTracking() (map[string]Tracking, error) {
+	output, err := m.git("for-each-ref", "--format=%(refname:short)%00%(upstream:short)%00%(upstream:track)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read branch tracking: %s", errOutput(err))
+	}
+
+	return parseBranchTracking(string(output)), nil
+}
+
+// parseBranchTracking parses for-each-ref output of NUL-separated branch,
+// upstream and %(upstream:track) fields, e.g. "[ahead 1, behind 2]"
+func parseBranchTracking(output string) map[string]Tracking {
+	tracking := make(map[string]Tracking)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 || fields[1] == "" {
+			continue
+		}
+
+		t := Tracking{Upstream: fields[1]}
+		track := strings.Trim(fields[2], "[]")
+		if track == "gone" {
+			t.Gone = true
+		}
+		for _, part := range strings.Split(track, ", ") {
+			kind, count, ok := strings.Cut(part, " ")
+			if !ok {
+				continue
+			}
+			n, _ := strconv.Atoi(count)
+			switch kind {
+			case "ahead":
+				t.Ahead = n
+			case "behind":
+				t.Behind = n
+			}
+		}
+		tracking[fields[0]] = t
+	}
+	return tracking
+}
@@ -0,0 +1,227 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultBranch(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	// Falls back to the main worktree's branch
+	runGit(t, repoPath, "branch", "-m", "main", "trunk")
+	if got := mgr.DefaultBranch(); got != "trunk" {
+		t.Errorf("DefaultBranch() = %q, want trunk", got)
+	}
+
+	// origin/HEAD wins over the main worktree
+	runGit(t, repoPath, "update-ref", "refs/remotes/origin/develop", "HEAD")
+	runGit(t, repoPath, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/develop")
+	if got := mgr.DefaultBranch(); got != "origin/develop" {
+		t.Errorf("DefaultBranch() = %q, want origin/develop", got)
+	}
+	runGit(t, repoPath, "branch", "develop")
+	if got := mgr.DefaultBranch(); got != "develop" {
+		t.Errorf("DefaultBranch() = %q, want develop", got)
+	}
+
+	// A per-repo override wins over everything
+	if err := mgr.SetDefaultBranch("release"); err != nil {
+		t.Fatalf("SetDefaultBranch failed: %v", err)
+	}
+	if got := mgr.DefaultBranch(); got != "release" {
+		t.Errorf("DefaultBranch() = %q, want release", got)
+	}
+
+	if err := mgr.SetDefaultBranch(""); err != nil {
+		t.Fatalf("SetDefaultBranch(\"\") failed: %v", err)
+	}
+	if err := mgr.SetDefaultBranch(""); err != nil {
+		t.Errorf("unsetting twice should not fail: %v", err)
+	}
+	if got := mgr.DefaultBranch(); got != "develop" {
+		t.Errorf("DefaultBranch() = %q, want develop", got)
+	}
+
+	// Add uses the detected branch when no base is given
+	if _, err := mgr.Add("feat", "feat", ""); err != nil {
+		t.Fatalf("Add without base failed: %v", err)
+	}
+}
+
+func TestAddTracksRemoteBranch(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	// Remote-tracking refs are enough; nothing needs to be fetched
+	runGit(t, repoPath, "remote", "add", "origin", "https://example.com/fork.git")
+	runGit(t, repoPath, "remote", "add", "upstream", "https://example.com/project.git")
+	runGit(t, repoPath, "update-ref", "refs/remotes/upstream/only-upstream", "HEAD")
+	runGit(t, repoPath, "update-ref", "refs/remotes/origin/shared", "HEAD")
+	runGit(t, repoPath, "update-ref", "refs/remotes/upstream/shared", "HEAD")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	result, err := mgr.AddWithOptions(AddOptions{Name: "only-upstream", Branch: "only-upstream"})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if result.Upstream != "upstream/only-upstream" {
+		t.Errorf("Upstream = %q, want upstream/only-upstream", result.Upstream)
+	}
+	if got := strings.TrimSpace(runGit(t, repoPath, "rev-parse", "--abbrev-ref", "only-upstream@{upstream}")); got != "upstream/only-upstream" {
+		t.Errorf("configured upstream = %q, want upstream/only-upstream", got)
+	}
+
+	// Several remotes have the branch and none is preferred
+	_, err = mgr.AddWithOptions(AddOptions{Name: "shared", Branch: "shared"})
+	var ambiguous *AmbiguousBranchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousBranchError, got %v", err)
+	}
+	if len(ambiguous.Remotes) != 2 {
+		t.Errorf("Remotes = %v, want both remotes", ambiguous.Remotes)
+	}
+
+	// The preferred remote resolves the ambiguity
+	mgr.SetPreferredRemote("upstream")
+	result, err = mgr.AddWithOptions(AddOptions{Name: "shared", Branch: "shared"})
+	if err != nil {
+		t.Fatalf("AddWithOptions with preferred remote failed: %v", err)
+	}
+	if result.Upstream != "upstream/shared" {
+		t.Errorf("Upstream = %q, want upstream/shared", result.Upstream)
+	}
+
+	// An explicit remote must have the branch
+	if _, err := mgr.AddWithOptions(AddOptions{Name: "x", Branch: "only-upstream-2", Remote: "origin"}); err == nil {
+		t.Error("expected error for branch missing on requested remote")
+	}
+}
+
+func TestBranchTracking(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	wt0 := findWorktree(t, mgr, "wt-0")
+
+	// branch-0 tracks main and is one commit ahead
+	runGit(t, repoPath, "branch", "--set-upstream-to=main", "branch-0")
+	runGit(t, wt0.Path, "commit", "--allow-empty", "-m", "ahead")
+
+	// branch-1 tracks a remote branch that has been deleted
+	runGit(t, repoPath, "remote", "add", "origin", "/nonexistent")
+	runGit(t, repoPath, "update-ref", "refs/remotes/origin/branch-1", "HEAD")
+	runGit(t, repoPath, "branch", "--set-upstream-to=origin/branch-1", "branch-1")
+	runGit(t, repoPath, "update-ref", "-d", "refs/remotes/origin/branch-1")
+
+	tracking, err := mgr.BranchTracking()
+	if err != nil {
+		t.Fatalf("BranchTracking failed: %v", err)
+	}
+
+	if got := tracking["branch-0"]; got != (Tracking{Upstream: "main", Ahead: 1}) {
+		t.Errorf("branch-0 tracking = %+v", got)
+	}
+	if got := tracking["branch-1"]; !got.Gone || got.Upstream != "origin/branch-1" {
+		t.Errorf("branch-1 tracking = %+v, want gone", got)
+	}
+	if _, ok := tracking["main"]; ok {
+		t.Error("expected branches without upstream to be omitted")
+	}
+}
+
+func TestParseBranchTracking(t *testing.T) {
+	output := "a\x00origin/a\x00[ahead 2, behind 3]\nb\x00origin/b\x00\nc\x00\x00\n"
+	tracking := parseBranchTracking(output)
+
+	if got := tracking["a"]; got.Ahead != 2 || got.Behind != 3 {
+		t.Errorf("a = %+v, want ahead 2 behind 3", got)
+	}
+	if got, ok := tracking["b"]; !ok || got.Ahead != 0 || got.Behind != 0 {
+		t.Errorf("b = %+v, want in sync", got)
+	}
+	if _, ok := tracking["c"]; ok {
+		t.Error("expected c without upstream to be omitted")
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	branchOff := func(branch string) {
+		t.Helper()
+		runGit(t, repoPath, "checkout", "-q", "-b", branch)
+		runGit(t, repoPath, "commit", "-q", "--allow-empty", "-m", branch)
+		runGit(t, repoPath, "checkout", "-q", "main")
+	}
+
+	remotePath := t.TempDir()
+	runGit(t, repoPath, "init", "-q", "--bare", remotePath)
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+
+	runGit(t, repoPath, "branch", "merged")
+	branchOff("local-only")
+	branchOff("pushed")
+	runGit(t, repoPath, "push", "-q", "-u", "origin", "pushed")
+	branchOff("unpushed")
+	runGit(t, repoPath, "push", "-q", "-u", "origin", "unpushed")
+	runGit(t, repoPath, "checkout", "-q", "unpushed")
+	runGit(t, repoPath, "commit", "-q", "--allow-empty", "-m", "more")
+	runGit(t, repoPath, "checkout", "-q", "main")
+	branchOff("gone")
+	runGit(t, repoPath, "push", "-q", "-u", "origin", "gone")
+	runGit(t, repoPath, "push", "-q", "origin", "--delete", "gone")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	for _, branch := range []string{"merged", "pushed"} {
+		if err := mgr.DeleteBranch(branch, "main", false); err != nil {
+			t.Errorf("DeleteBranch(%s) failed: %v", branch, err)
+		}
+		if mgr.localBranchExists(branch) {
+			t.Errorf("expected %s to be deleted", branch)
+		}
+	}
+
+	for branch, want := range map[string]string{
+		"local-only": "has no upstream",
+		"gone":       "its upstream is gone",
+		"unpushed":   "1 commit(s) not merged into main or pushed to origin/unpushed",
+	} {
+		err := mgr.DeleteBranch(branch, "main", false)
+		var unsafe *UnsafeBranchError
+		if !errors.As(err, &unsafe) || !strings.Contains(unsafe.Reason, want) {
+			t.Errorf("DeleteBranch(%s) = %v, want UnsafeBranchError containing %q", branch, err, want)
+		}
+		if !mgr.localBranchExists(branch) {
+			t.Errorf("expected %s to be kept", branch)
+		}
+	}
+
+	// Forcing deletes regardless, but never the base branch
+	if err := mgr.DeleteBranch("unpushed", "main", true); err != nil || mgr.localBranchExists("unpushed") {
+		t.Errorf("forced DeleteBranch failed: %v", err)
+	}
+	if err := mgr.DeleteBranch("main", "main", true); err == nil {
+		t.Error("expected the base branch to be protected")
+	}
+}
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// carryDir holds backup patches of carried changes, inside the git directory
+const carryDir = "wtx-carry"
+
+// CarryOptions controls what Carry moves
+type CarryOptions struct {
+	// Untracked carries untracked (but not ignored) files too
+	Untracked bool
+	// Keep leaves the source worktree's changes in place
+	Keep bool
+}
+
+// CarryResult describes carried changes
+type CarryResult struct {
+	// Files lists the paths that were carried
+	Files []string
+	// Conflicts lists paths the 3-way merge couldn't resolve. They are left
+	// unmerged in the target to resolve, while the rest of its index is
+	// restored; the source is left untouched.
+	Conflicts []string
+	// Backup is a patch of everything carried, kept in case anything goes
+	// wrong
+	Backup string
+	// Cleaned is set when the changes were removed from the source
+	Cleaned bool
+}
+
+// Carry moves uncommitted changes from one worktree to another. The changes
+// are captured as a patch against the source's HEAD, like git stash does,
+// and applied to the target with a 3-way merge so they carry over even if
+// the target is at a different commit. The source is only cleaned after the
+// patch applied without conflicts.
+func (m *Manager) Carry(fromPath, toPath string, opts CarryOptions) (*CarryResult, error) {
+	tree, err := m.snapshot(fromPath, opts.Untracked)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without rename detection a renamed file lists both of its paths
+	output, err := m.gitIn(fromPath, "diff", "--name-only", "--no-renames", "-z", "HEAD", tree, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %s", errOutput(err))
+	}
+	files := splitNUL(output)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no uncommitted changes to carry")
+	}
+
+	patch, err := m.gitIn(fromPath, "diff", "--binary", "--full-index", "HEAD", tree, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch: %s", errOutput(err))
+	}
+
+	result := &CarryResult{Files: files}
+	if result.Backup, err = m.saveCarryBackup(fromPath, patch); err != nil {
+		return nil, err
+	}
+
+	// Remember what the target had staged, since --3way stages what it
+	// applies on top of it
+	index, err := m.gitIn(toPath, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the target's index: %s", errOutput(err))
+	}
+
+	if _, err := m.run(Command{
+		Dir:       toPath,
+		Args:      []string{"apply", "--3way", "--whitespace=nowarn"},
+		Stdin:     bytes.NewReader(patch),
+		NoTimeout: true,
+	}); err != nil {
+		conflicts, _ := m.gitIn(toPath, "diff", "--name-only", "-z", "--diff-filter=U")
+		result.Conflicts = splitNUL(conflicts)
+		if len(result.Conflicts) == 0 {
+			// git apply is atomic, so nothing changed in the target
+			return nil, fmt.Errorf("failed to apply changes: %s", errOutput(err))
+		}
+
+		// Files that applied cleanly are unstaged just as without conflicts
+		conflicted := make(map[string]bool, len(result.Conflicts))
+		for _, file := range result.Conflicts {
+			conflicted[file] = true
+		}
+		var applied []string
+		for _, file := range files {
+			if !conflicted[file] {
+				applied = append(applied, file)
+			}
+		}
+		if err := m.restoreIndex(toPath, index, applied); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+
+	// Put the target's index back so carried changes are left unstaged like
+	// git stash apply does, keeping anything the target had staged itself
+	if err := m.restoreIndex(toPath, index, files); err != nil {
+		return nil, err
+	}
+
+	if opts.Keep {
+		return result, nil
+	}
+
+	if _, err := m.gitWrite(fromPath, "reset", "-q", "--hard", "HEAD"); err != nil {
+		return result, fmt.Errorf("changes were carried but cleaning the source failed: %s", errOutput(err))
+	}
+	if opts.Untracked {
+		clean := append([]string{"clean", "-q", "-f", "--"}, files...)
+		if _, err := m.gitWrite(fromPath, clean...); err != nil {
+			return result, fmt.Errorf("changes were carried but removing untracked files failed: %s", errOutput(err))
+		}
+	}
+	result.Cleaned = true
+	return result, nil
+}
+
+// restoreIndex resets files in a worktree's index to the tree write-tree
+// recorded before carrying, leaving the working tree alone
+func (m *Manager) restoreIndex(worktreePath string, index []byte, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	reset := append([]string{"reset", "-q", strings.TrimSpace(string(index)), "--"}, files...)
+	if _, err := m.gitWrite(worktreePath, reset...); err != nil {
+		return fmt.Errorf("failed to unstage carried changes: %s", errOutput(err))
+	}
+	return nil
+}
+
+// splitNUL splits NUL-terminated git output
+func splitNUL(output []byte) []string {
+	var fields []string
+	for _, f := range strings.Split(string(output), "\x00") {
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// saveCarryBackup writes a carried patch under the git directory and returns
+// its path
+func (m *Manager) saveCarryBackup(fromPath string, patch []byte) (string, error) {
+	dir := filepath.Join(m.GitDir(), carryDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.patch", filepath.Base(fromPath), time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, patch, 0644); err != nil {
+		return "", fmt.Errorf("failed to write backup patch: %w", err)
+	}
+	return path, nil
+}
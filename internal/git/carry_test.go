@@ -0,0 +1,200 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCarry(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	from := findWorktree(t, mgr, "wt-0")
+	to := findWorktree(t, mgr, "wt-1")
+
+	write := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := mgr.Carry(from.Path, to.Path, CarryOptions{}); err == nil {
+		t.Error("Carry with no changes should fail")
+	}
+
+	// The target is a commit ahead, so the patch needs the 3-way merge
+	write(to.Path, "other.txt", "other\n")
+	runGit(t, to.Path, "add", "other.txt")
+	runGit(t, to.Path, "commit", "-q", "-m", "Add other.txt")
+
+	write(from.Path, "README.md", "# Test\nchanged\n")
+	write(from.Path, "staged.txt", "staged\n")
+	runGit(t, from.Path, "add", "staged.txt")
+	write(from.Path, "untracked file.txt", "untracked\n")
+
+	result, err := mgr.Carry(from.Path, to.Path, CarryOptions{Untracked: true, Keep: true})
+	if err != nil {
+		t.Fatalf("Carry failed: %v", err)
+	}
+	if got := strings.Join(result.Files, ","); got != "README.md,staged.txt,untracked file.txt" {
+		t.Errorf("carried files = %q", got)
+	}
+	if result.Cleaned || len(result.Conflicts) != 0 {
+		t.Errorf("Carry with Keep = %+v", result)
+	}
+	if !fileExists(result.Backup) {
+		t.Errorf("backup %s not written", result.Backup)
+	}
+	for _, file := range []string{"README.md", "staged.txt", "untracked file.txt"} {
+		if !fileExists(filepath.Join(from.Path, file)) || !fileExists(filepath.Join(to.Path, file)) {
+			t.Errorf("%s should be in both worktrees", file)
+		}
+	}
+
+	// Carried changes are left unstaged
+	if status := runGit(t, to.Path, "status", "--porcelain"); status != " M README.md\n?? staged.txt\n?? \"untracked file.txt\"\n" {
+		t.Errorf("target status = %q", status)
+	}
+
+	// Once the target has committed a different change, carrying conflicts
+	// and the source keeps its changes
+	runGit(t, to.Path, "add", "README.md")
+	runGit(t, to.Path, "commit", "-q", "-m", "Change README")
+	runGit(t, to.Path, "clean", "-q", "-f")
+	runGit(t, from.Path, "reset", "-q", "--hard")
+	runGit(t, from.Path, "clean", "-q", "-f")
+	write(from.Path, "README.md", "# Test\nother change\n")
+
+	result, err = mgr.Carry(from.Path, to.Path, CarryOptions{})
+	if err != nil {
+		t.Fatalf("Carry with conflicts failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "README.md" || result.Cleaned {
+		t.Errorf("conflicting Carry = %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(from.Path, "README.md")); string(data) != "# Test\nother change\n" {
+		t.Errorf("source changed after conflict: %q", data)
+	}
+	runGit(t, to.Path, "reset", "-q", "--hard")
+
+	// A clean apply empties the source
+	write(from.Path, "new.txt", "new\n")
+	runGit(t, from.Path, "checkout", "-q", "--", "README.md")
+	result, err = mgr.Carry(from.Path, to.Path, CarryOptions{Untracked: true})
+	if err != nil {
+		t.Fatalf("Carry failed: %v", err)
+	}
+	if !result.Cleaned || !fileExists(filepath.Join(to.Path, "new.txt")) {
+		t.Errorf("Carry = %+v", result)
+	}
+	if status := runGit(t, from.Path, "status", "--porcelain"); status != "" {
+		t.Errorf("source not clean: %q", status)
+	}
+}
+
+func TestCarryKeepsTargetIndex(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	from := findWorktree(t, mgr, "wt-0")
+	to := findWorktree(t, mgr, "wt-1")
+
+	write := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(from.Path, "list.txt", "a\nb\nc\nd\ne\n")
+	write(from.Path, "old.txt", "old\n")
+	runGit(t, from.Path, "add", "list.txt", "old.txt")
+	runGit(t, from.Path, "commit", "-q", "-m", "Add files")
+	runGit(t, to.Path, "merge", "-q", "--ff-only", "branch-0")
+
+	// The target stages its own change to a file that gets carried
+	write(to.Path, "list.txt", "A\nb\nc\nd\ne\n")
+	runGit(t, to.Path, "add", "list.txt")
+
+	write(from.Path, "list.txt", "a\nb\nc\nd\nE\n")
+	runGit(t, from.Path, "mv", "old.txt", "renamed.txt")
+
+	result, err := mgr.Carry(from.Path, to.Path, CarryOptions{})
+	if err != nil {
+		t.Fatalf("Carry failed: %v", err)
+	}
+	if got := strings.Join(result.Files, ","); got != "list.txt,old.txt,renamed.txt" {
+		t.Errorf("carried files = %q", got)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(to.Path, "list.txt")); string(data) != "A\nb\nc\nd\nE\n" {
+		t.Errorf("target list.txt = %q", data)
+	}
+	// Only the target's own change stays staged, and nothing of the rename is
+	if staged := runGit(t, to.Path, "diff", "--cached", "--name-only"); staged != "list.txt\n" {
+		t.Errorf("staged = %q", staged)
+	}
+	if staged := runGit(t, to.Path, "show", ":list.txt"); staged != "A\nb\nc\nd\ne\n" {
+		t.Errorf("staged list.txt = %q", staged)
+	}
+	if status := runGit(t, to.Path, "status", "--porcelain"); status != "MM list.txt\n D old.txt\n?? renamed.txt\n" {
+		t.Errorf("target status = %q", status)
+	}
+}
+
+func TestCarryConflictRestoresTargetIndex(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	from := findWorktree(t, mgr, "wt-0")
+	to := findWorktree(t, mgr, "wt-1")
+
+	write := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The target commits a different README and stages a change of its own
+	write(to.Path, "README.md", "# Test\ntarget change\n")
+	runGit(t, to.Path, "commit", "-q", "-am", "Change README")
+	write(to.Path, "own.txt", "own\n")
+	runGit(t, to.Path, "add", "own.txt")
+
+	write(from.Path, "README.md", "# Test\nsource change\n")
+	write(from.Path, "new.txt", "new\n")
+	runGit(t, from.Path, "add", "new.txt")
+
+	result, err := mgr.Carry(from.Path, to.Path, CarryOptions{})
+	if err != nil {
+		t.Fatalf("Carry failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "README.md" || result.Cleaned {
+		t.Fatalf("conflicting Carry = %+v", result)
+	}
+
+	// The conflict is left to resolve, the cleanly applied file is unstaged
+	// and the target's own staged change is still staged
+	if status := runGit(t, to.Path, "status", "--porcelain"); status != "UU README.md\nA  own.txt\n?? new.txt\n" {
+		t.Errorf("target status = %q", status)
+	}
+	if data, _ := os.ReadFile(filepath.Join(to.Path, "new.txt")); string(data) != "new\n" {
+		t.Errorf("target new.txt = %q", data)
+	}
+}
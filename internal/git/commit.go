@@ -0,0 +1,214 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommitInfo summarizes the commit a worktree's HEAD points at
+type CommitInfo struct {
+	Hash    string
+	Subject string
+	Author  string
+	Time    time.Time
+	// Base is the branch SinceBase is counted against; empty if it couldn't
+	// be resolved
+	Base string
+	// SinceBase counts the commits on HEAD that aren't on Base
+	SinceBase int
+}
+
+// Age returns how long ago the commit was made, e.g. "3h ago" or "2w ago"
+func (c *CommitInfo) Age(now time.Time) string {
+	d := now.Sub(c.Time)
+	day := 24 * time.Hour
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 14*day:
+		return fmt.Sprintf("%dd ago", int(d/day))
+	case d < 60*day:
+		return fmt.Sprintf("%dw ago", int(d/(7*day)))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo ago", int(d/(30*day)))
+	}
+	return fmt.Sprintf("%dy ago", int(d/(365*day)))
+}
+
+// CommitInfos returns the last commit of every worktree, keyed by HEAD SHA,
+// with the number of commits on it that aren't on base. Commits and counts
+// come from a single git log walk of the heads down to base; only heads that
+// are already on base and not next to a walked commit need a second call. If
+// base can't be resolved the counts are left at zero.
+func (m *Manager) CommitInfos(worktrees []Worktree, base string) (map[string]*CommitInfo, error) {
+	var heads []string
+	seen := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.Head != "" && !seen[wt.Head] {
+			seen[wt.Head] = true
+			heads = append(heads, wt.Head)
+		}
+	}
+
+	infos := make(map[string]*CommitInfo, len(heads))
+	if len(heads) == 0 {
+		return infos, nil
+	}
+
+	counted := ""
+	if base != "" {
+		if err := m.walkSince(heads, base, infos); err == nil {
+			counted = base
+			heads = missingHeads(heads, infos)
+		}
+	}
+	if len(heads) == 0 {
+		return infos, nil
+	}
+
+	output, err := m.run(Command{
+		Dir:   m.repo.Path,
+		Args:  []string{"log", "--no-walk=unsorted", "--stdin", "--format=%H%x00%an%x00%ct%x00%s"},
+		Stdin: strings.NewReader(strings.Join(heads, "\n") + "\n"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %s", errOutput(err))
+	}
+	for _, info := range parseCommitInfos(string(output)) {
+		// Heads the walk didn't reach are on base
+		info.Base = counted
+		infos[info.Hash] = info
+	}
+	return infos, nil
+}
+
+// walkSince lists the commits reachable from heads but not from base, plus
+// the boundary commits on base next to them, and records the heads among
+// them in infos with their counts since base.
+//
+// git lists children before parents, so one pass counts every head: each
+// commit passes the set of heads that reach it on to its parents, and
+// counts once for each of those heads.
+func (m *Manager) walkSince(heads []string, base string, infos map[string]*CommitInfo) error {
+	output, err := m.run(Command{
+		Dir:   m.repo.Path,
+		Args:  []string{"log", "--stdin", "--topo-order", "--boundary", "--format=%m%H%x00%P%x00%an%x00%ct%x00%s"},
+		Stdin: strings.NewReader(strings.Join(heads, "\n") + "\n^" + base + "\n"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count commits since %s: %s", base, errOutput(err))
+	}
+
+	index := make(map[string]int, len(heads))
+	for i, head := range heads {
+		index[head] = i
+	}
+	words := (len(heads) + 63) / 64
+	reachedBy := make(map[string][]uint64)
+	counts := make([]int, len(heads))
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if line == "" {
+			continue
+		}
+		boundary := line[0] == '-'
+		fields := strings.SplitN(line[1:], "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		hash := fields[0]
+
+		if i, ok := index[hash]; ok {
+			if info := parseCommitInfo(hash, fields[2], fields[3], fields[4]); info != nil {
+				info.Base = base
+				infos[hash] = info
+			}
+			if !boundary {
+				if reachedBy[hash] == nil {
+					reachedBy[hash] = make([]uint64, words)
+				}
+				reachedBy[hash][i/64] |= 1 << (i % 64)
+			}
+		}
+		if boundary {
+			// Boundary commits are on base
+			continue
+		}
+
+		reached := reachedBy[hash]
+		delete(reachedBy, hash)
+		if reached == nil {
+			continue
+		}
+		for i := range heads {
+			if reached[i/64]&(1<<(i%64)) != 0 {
+				counts[i]++
+			}
+		}
+		for _, parent := range strings.Fields(fields[1]) {
+			p := reachedBy[parent]
+			if p == nil {
+				p = make([]uint64, words)
+				reachedBy[parent] = p
+			}
+			for w := range p {
+				p[w] |= reached[w]
+			}
+		}
+	}
+
+	for i, head := range heads {
+		if info, ok := infos[head]; ok {
+			info.SinceBase = counts[i]
+		}
+	}
+	return nil
+}
+
+// missingHeads returns the heads that have no entry in infos yet
+func missingHeads(heads []string, infos map[string]*CommitInfo) []string {
+	var missing []string
+	for _, head := range heads {
+		if _, ok := infos[head]; !ok {
+			missing = append(missing, head)
+		}
+	}
+	return missing
+}
+
+// parseCommitInfos parses NUL-separated hash, author, timestamp and subject
+// lines
+func parseCommitInfos(output string) []*CommitInfo {
+	var infos []*CommitInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		if info := parseCommitInfo(fields[0], fields[1], fields[2], fields[3]); info != nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// parseCommitInfo builds a CommitInfo from its formatted fields, or returns
+// nil if the timestamp is malformed
+func parseCommitInfo(hash, author, timestamp, subject string) *CommitInfo {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &CommitInfo{
+		Hash:    hash,
+		Author:  author,
+		Time:    time.Unix(seconds, 0),
+		Subject: subject,
+	}
+}
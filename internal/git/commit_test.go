@@ -0,0 +1,112 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommitInfos(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	wt := findWorktree(t, mgr, "wt-0")
+
+	for _, file := range []string{"one.txt", "two.txt"} {
+		if err := os.WriteFile(filepath.Join(wt.Path, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, wt.Path, "add", file)
+		runGit(t, wt.Path, "commit", "-q", "-m", "Add "+file)
+	}
+
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	infos, err := mgr.CommitInfos(worktrees, "main")
+	if err != nil {
+		t.Fatalf("CommitInfos failed: %v", err)
+	}
+
+	for _, w := range worktrees {
+		info, ok := infos[w.Head]
+		if !ok {
+			t.Fatalf("no commit info for %s", w.Name)
+		}
+
+		wantSubject, wantCount := "Initial commit", 0
+		if w.Name == "wt-0" {
+			wantSubject, wantCount = "Add two.txt", 2
+		}
+		if info.Subject != wantSubject || info.SinceBase != wantCount || info.Base != "main" {
+			t.Errorf("%s: got %q with %d since %q, want %q with %d since main",
+				w.Name, info.Subject, info.SinceBase, info.Base, wantSubject, wantCount)
+		}
+		if info.Author == "" || info.Time.IsZero() {
+			t.Errorf("%s: missing author or time: %+v", w.Name, info)
+		}
+	}
+
+	// Heads sharing commits, and merges, count each commit once
+	wt1 := findWorktree(t, mgr, "wt-1")
+	runGit(t, wt1.Path, "reset", "-q", "--hard", "branch-0~1")
+	if err := os.WriteFile(filepath.Join(wt1.Path, "three.txt"), []byte("three"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, wt1.Path, "add", "three.txt")
+	runGit(t, wt1.Path, "commit", "-q", "-m", "Add three.txt")
+	runGit(t, wt.Path, "merge", "-q", "--no-ff", "-m", "Merge branch-1", "branch-1")
+
+	worktrees, err = mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	infos, err = mgr.CommitInfos(worktrees, "main")
+	if err != nil {
+		t.Fatalf("CommitInfos failed: %v", err)
+	}
+	want := map[string]int{"wt-0": 4, "wt-1": 2}
+	for _, w := range worktrees {
+		info := infos[w.Head]
+		if info == nil {
+			t.Fatalf("no commit info for %s", w.Name)
+		}
+		if info.SinceBase != want[w.Name] || info.Base != "main" {
+			t.Errorf("%s: got %d since %q, want %d since main", w.Name, info.SinceBase, info.Base, want[w.Name])
+		}
+	}
+
+	// An unknown base still returns the commits
+	infos, err = mgr.CommitInfos(worktrees, "no-such-branch")
+	if err != nil || len(infos) != 3 {
+		t.Errorf("CommitInfos with unknown base = %d infos, %v", len(infos), err)
+	}
+}
+
+func TestCommitAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{2 * 24 * time.Hour, "2d ago"},
+		{21 * 24 * time.Hour, "3w ago"},
+		{90 * 24 * time.Hour, "3mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+	}
+	for _, tt := range tests {
+		info := &CommitInfo{Time: now.Add(-tt.ago)}
+		if got := info.Age(now); got != tt.want {
+			t.Errorf("Age(%s) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
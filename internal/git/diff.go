@@ -0,0 +1,170 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiffOptions controls the output of Diff
+type DiffOptions struct {
+	// Stat prints a diffstat instead of the patch
+	Stat bool
+	// NameOnly prints only the names of changed files
+	NameOnly bool
+	// Color forces colored output, for printing to a terminal
+	Color bool
+}
+
+// CommitSummary is a one-line description of a commit
+type CommitSummary struct {
+	Hash    string
+	Subject string
+}
+
+// DiffStat returns a diffstat of the changes on head since it diverged from
+// base, like a pull request shows them
+func (m *Manager) DiffStat(base, head string) (string, error) {
+	output, err := m.git("diff", "--stat", base+"..."+head, "--")
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s against %s: %s", head, base, errOutput(err))
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// Diff returns the difference between two commits or trees
+func (m *Manager) Diff(from, to string, opts DiffOptions) (string, error) {
+	args := []string{"diff"}
+	switch {
+	case opts.NameOnly:
+		args = append(args, "--name-only")
+	case opts.Stat:
+		args = append(args, "--stat")
+	}
+	if opts.Color {
+		args = append(args, "--color=always")
+	}
+	args = append(args, from, to, "--")
+
+	output, err := m.git(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff: %s", errOutput(err))
+	}
+	return string(output), nil
+}
+
+// DivergentCommits returns the commits reachable only from a and only from b
+func (m *Manager) DivergentCommits(a, b string) (onlyA, onlyB []CommitSummary, err error) {
+	output, err := m.git("log", "--left-right", "--format=%m%x00%h%x00%s", a+"..."+b, "--")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compare commits: %s", errOutput(err))
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commit := CommitSummary{Hash: fields[1], Subject: fields[2]}
+		if fields[0] == "<" {
+			onlyA = append(onlyA, commit)
+		} else {
+			onlyB = append(onlyB, commit)
+		}
+	}
+	return onlyA, onlyB, nil
+}
+
+// Snapshot returns a tree of a worktree's current contents, including staged,
+// unstaged and untracked (but not ignored) changes. It stages into a
+// temporary copy of the index, so the worktree's own index is left alone.
+func (m *Manager) Snapshot(worktreePath string) (string, error) {
+	return m.snapshot(worktreePath, true)
+}
+
+// snapshot writes a tree of a worktree's staged and unstaged changes, plus
+// untracked files if requested
+func (m *Manager) snapshot(worktreePath string, untracked bool) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "wtx-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	index := filepath.Join(tmpDir, "index")
+	env := []string{"GIT_INDEX_FILE=" + index}
+
+	// Starting from the real index lets git reuse its cached file stats
+	if err := m.copyIndex(worktreePath, index); err != nil {
+		if _, err := m.run(Command{Dir: worktreePath, Args: []string{"read-tree", "HEAD"}, Env: env}); err != nil {
+			return "", fmt.Errorf("failed to snapshot %s: %s", worktreePath, errOutput(err))
+		}
+	}
+	add := []string{"add", "-u"}
+	if untracked {
+		add = []string{"add", "-A"}
+	}
+	if _, err := m.run(Command{Dir: worktreePath, Args: add, Env: env, NoTimeout: true}); err != nil {
+		return "", fmt.Errorf("failed to snapshot %s: %s", worktreePath, errOutput(err))
+	}
+
+	output, err := m.run(Command{Dir: worktreePath, Args: []string{"write-tree"}, Env: env})
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot %s: %s", worktreePath, errOutput(err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ExportFile writes the contents of path at a commit or tree to dest. It
+// reports false if the file doesn't exist there.
+func (m *Manager) ExportFile(rev, path, dest string) (bool, error) {
+	output, err := m.git("cat-file", "blob", rev+":"+filepath.ToSlash(path))
+	if err != nil {
+		if exitCode(err) == 128 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s at %s: %s", path, rev, errOutput(err))
+	}
+	if err := os.WriteFile(dest, output, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// copyIndex copies a worktree's index file to dst
+func (m *Manager) copyIndex(worktreePath, dst string) error {
+	output, err := m.gitIn(worktreePath, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return err
+	}
+	src := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(worktreePath, src)
+	}
+	return copyFile(src, dst)
+}
+
+// copyFile copies src to dst, keeping its permissions
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffWorktrees(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	a := findWorktree(t, mgr, "wt-0")
+	b := findWorktree(t, mgr, "wt-1")
+
+	write := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(a.Path, "impl.go", "package a\n")
+	runGit(t, a.Path, "add", "impl.go")
+	runGit(t, a.Path, "commit", "-q", "-m", "Implement in a")
+	a = findWorktree(t, mgr, "wt-0")
+
+	onlyA, onlyB, err := mgr.DivergentCommits(a.Head, b.Head)
+	if err != nil {
+		t.Fatalf("DivergentCommits failed: %v", err)
+	}
+	if len(onlyA) != 1 || onlyA[0].Subject != "Implement in a" || len(onlyB) != 0 {
+		t.Errorf("DivergentCommits = %v, %v; want one commit only on a", onlyA, onlyB)
+	}
+
+	names, err := mgr.Diff(a.Head, b.Head, DiffOptions{NameOnly: true})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if strings.TrimSpace(names) != "impl.go" {
+		t.Errorf("Diff names = %q, want impl.go", names)
+	}
+
+	// Uncommitted and untracked changes only show up in snapshots
+	write(b.Path, "README.md", "changed\n")
+	write(b.Path, "notes.txt", "draft\n")
+
+	from, err := mgr.Snapshot(a.Path)
+	if err != nil {
+		t.Fatalf("Snapshot(a) failed: %v", err)
+	}
+	to, err := mgr.Snapshot(b.Path)
+	if err != nil {
+		t.Fatalf("Snapshot(b) failed: %v", err)
+	}
+	names, err = mgr.Diff(from, to, DiffOptions{NameOnly: true})
+	if err != nil {
+		t.Fatalf("Diff of snapshots failed: %v", err)
+	}
+	if got := strings.Fields(names); strings.Join(got, " ") != "README.md impl.go notes.txt" {
+		t.Errorf("snapshot diff names = %v", got)
+	}
+
+	// Snapshotting leaves the real index alone
+	if status, err := mgr.GetStatus(b.Path); err != nil || status.Staged != 0 || status.Untracked != 1 {
+		t.Errorf("status after snapshot = %+v, %v", status, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "impl.go")
+	if found, err := mgr.ExportFile(a.Head, "impl.go", dest); err != nil || !found {
+		t.Fatalf("ExportFile = %v, %v", found, err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "package a\n" {
+		t.Errorf("exported %q", data)
+	}
+	if found, err := mgr.ExportFile(b.Head, "impl.go", dest); err != nil || found {
+		t.Errorf("ExportFile of missing file = %v, %v", found, err)
+	}
+}
@@ -1,11 +1,13 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // setupTestRepo creates a temporary git repository with some commits and worktrees
@@ -79,6 +81,22 @@ func setupTestRepo(t *testing.T, numWorktrees int) (string, func(), error) {
 	return repoDir, cleanup, nil
 }
 
+// runGit runs git in dir and returns its output, failing the test if git
+// fails. Commits get a test identity even in repositories without one.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
 func TestGetStatus(t *testing.T) {
 	repoPath, cleanup, err := setupTestRepo(t, 1)
 	if err != nil {
@@ -190,3 +208,191 @@ func TestGetStatuses(t *testing.T) {
 		}
 	}
 }
+
+// slowRunner blocks git status in one directory until the context is done
+type slowRunner struct {
+	slowDir string
+}
+
+func (r *slowRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	if c.Dir == r.slowDir {
+		<-ctx.Done()
+		return nil, &CommandError{Args: c.Args, ExitCode: -1, Stderr: "timed out", Err: ctx.Err()}
+	}
+	return []byte("# branch.oid abc123\n# branch.head main\n"), nil
+}
+
+func TestStreamStatuses(t *testing.T) {
+	dir := t.TempDir()
+	var worktrees []Worktree
+	for i := 0; i < 5; i++ {
+		worktrees = append(worktrees, Worktree{Name: fmt.Sprintf("wt-%d", i), Path: filepath.Join(dir, fmt.Sprintf("wt-%d", i))})
+	}
+
+	mgr := NewManager(&Repository{Path: dir})
+	mgr.SetRunner(&slowRunner{slowDir: worktrees[2].Path})
+	mgr.SetStatusWorkers(2)
+
+	seen := make(map[string]StatusResult)
+	for result := range mgr.StreamStatuses(context.Background(), worktrees, 50*time.Millisecond) {
+		seen[result.Worktree.Path] = result
+	}
+
+	if len(seen) != len(worktrees) {
+		t.Fatalf("Expected %d results, got %d", len(worktrees), len(seen))
+	}
+	for i, wt := range worktrees {
+		result := seen[wt.Path]
+		if i == 2 {
+			if !result.TimedOut() {
+				t.Errorf("Expected %s to time out, got %v", wt.Name, result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || result.Status == nil || !result.Status.Clean {
+			t.Errorf("Expected clean status for %s, got %+v", wt.Name, result)
+		}
+	}
+}
+
+func TestFindRepo(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 1)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	// Resolve symlinks (e.g. /tmp on macOS) so paths compare equal
+	repoPath, _ = filepath.EvalSymlinks(repoPath)
+	wtPath := filepath.Join(filepath.Dir(repoPath), "wt-0")
+	subDir := filepath.Join(wtPath, "sub")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{repoPath, wtPath, subDir} {
+		repo, err := FindRepo(start)
+		if err != nil {
+			t.Fatalf("FindRepo(%s) failed: %v", start, err)
+		}
+		if repo.Path != repoPath {
+			t.Errorf("FindRepo(%s).Path = %q, want %q", start, repo.Path, repoPath)
+		}
+		if repo.GitDir != filepath.Join(repoPath, ".git") {
+			t.Errorf("FindRepo(%s).GitDir = %q, want common dir", start, repo.GitDir)
+		}
+		if repo.IsBare {
+			t.Errorf("FindRepo(%s).IsBare = true, want false", start)
+		}
+	}
+}
+
+func TestFindRepoBare(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	tmpDir, _ := filepath.EvalSymlinks(filepath.Dir(repoPath))
+	barePath := filepath.Join(tmpDir, "bare.git")
+	wtPath := filepath.Join(tmpDir, "bare-wt")
+
+	runGit(t, tmpDir, "clone", "-q", "--bare", repoPath, barePath)
+	runGit(t, barePath, "worktree", "add", "-q", wtPath, "main")
+
+	for _, start := range []string{barePath, wtPath} {
+		repo, err := FindRepo(start)
+		if err != nil {
+			t.Fatalf("FindRepo(%s) failed: %v", start, err)
+		}
+		if repo.GitDir != barePath || repo.Path != barePath {
+			t.Errorf("FindRepo(%s) = %+v, want bare repo at %s", start, repo, barePath)
+		}
+		if !repo.IsBare {
+			t.Errorf("FindRepo(%s).IsBare = false, want true", start)
+		}
+	}
+}
+
+func TestParseStatusV2(t *testing.T) {
+	output := `# branch.oid 5e2eb34547ab92c40aea384c0bee9e52aeeea9e1
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -1
+1 M. N... 100644 100644 100644 aaaa bbbb staged.go
+1 .M N... 100644 100644 100644 aaaa bbbb unstaged.go
+1 MM N... 100644 100644 100644 aaaa bbbb both.go
+2 R. N... 100644 100644 100644 aaaa bbbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaaa bbbb cccc conflict.go
+? untracked.txt
+? other.txt
+`
+	s := parseStatusV2(output)
+
+	if s.Branch != "feature" || s.Detached {
+		t.Errorf("Branch = %q, Detached = %v", s.Branch, s.Detached)
+	}
+	if s.Upstream != "origin/feature" || s.UpstreamGone {
+		t.Errorf("Upstream = %q, UpstreamGone = %v", s.Upstream, s.UpstreamGone)
+	}
+	if s.Ahead != 2 || s.Behind != 1 {
+		t.Errorf("Ahead/Behind = %d/%d, want 2/1", s.Ahead, s.Behind)
+	}
+	if s.Staged != 3 || s.Unstaged != 2 || s.Untracked != 2 || s.Conflicted != 1 {
+		t.Errorf("counts = staged %d unstaged %d untracked %d conflicted %d",
+			s.Staged, s.Unstaged, s.Untracked, s.Conflicted)
+	}
+	if s.Clean || !s.HasChanges {
+		t.Error("expected dirty status")
+	}
+	if s.State() != "conflicted" {
+		t.Errorf("State() = %q, want conflicted", s.State())
+	}
+
+	gone := parseStatusV2("# branch.oid abc\n# branch.head feature\n# branch.upstream origin/feature\n")
+	if !gone.UpstreamGone || !gone.Clean {
+		t.Errorf("expected clean status with gone upstream, got %+v", gone)
+	}
+
+	detached := parseStatusV2("# branch.oid abc\n# branch.head (detached)\n")
+	if !detached.Detached || detached.Branch != "" {
+		t.Errorf("expected detached status, got %+v", detached)
+	}
+}
+
+func TestGetStatusOperation(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	readme := filepath.Join(repoPath, "README.md")
+	runGit(t, repoPath, "checkout", "-q", "-b", "other")
+	os.WriteFile(readme, []byte("other"), 0644)
+	runGit(t, repoPath, "commit", "-q", "-am", "other")
+	runGit(t, repoPath, "checkout", "-q", "main")
+	os.WriteFile(readme, []byte("main"), 0644)
+	runGit(t, repoPath, "commit", "-q", "-am", "main")
+
+	// The merge conflicts and exits non-zero; only the resulting state matters
+	merge := exec.Command("git", "merge", "other")
+	merge.Dir = repoPath
+	_ = merge.Run()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	status, err := mgr.GetStatus(repoPath)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if status.Operation != OperationMerge {
+		t.Errorf("Operation = %q, want %q", status.Operation, OperationMerge)
+	}
+	if status.Conflicted != 1 {
+		t.Errorf("Conflicted = %d, want 1", status.Conflicted)
+	}
+	if status.State() != "merging" {
+		t.Errorf("State() = %q, want merging", status.State())
+	}
+}
@@ -0,0 +1,355 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IncludeFile is the repository file listing ignored files that new
+// worktrees need, such as .env files, local certificates and IDE settings.
+// Each line is a gitignore pattern; matches are copied from the main
+// worktree, or symlinked to it under a [symlink] section. Blank lines and
+// lines starting with # are ignored:
+//
+//	.env
+//	certs/*.pem
+//
+//	[symlink]
+//	node_modules/
+const IncludeFile = ".wtxinclude"
+
+// IncludeMode is how an included path is brought into a worktree
+type IncludeMode string
+
+const (
+	IncludeCopy    IncludeMode = "copy"
+	IncludeSymlink IncludeMode = "symlink"
+)
+
+// IncludeStatus is what happened to an included path
+type IncludeStatus string
+
+const (
+	IncludeCreated   IncludeStatus = "created"
+	IncludeUpdated   IncludeStatus = "updated"
+	IncludeUnchanged IncludeStatus = "unchanged"
+	// IncludeSkipped means the worktree already has a different file there
+	IncludeSkipped IncludeStatus = "skipped"
+)
+
+// IncludedPath is one path brought into a worktree from the main worktree
+type IncludedPath struct {
+	Path   string
+	Mode   IncludeMode
+	Status IncludeStatus
+}
+
+// ParseIncludeFile reads patterns in the IncludeFile format, grouped by mode.
+// Patterns before any section are copied.
+func ParseIncludeFile(r io.Reader) (map[IncludeMode][]string, error) {
+	patterns := make(map[IncludeMode][]string)
+	mode := IncludeCopy
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Brackets also start gitignore character classes, so only a word in
+		// brackets is a section header
+		if section, ok := includeSection(line); ok {
+			switch IncludeMode(section) {
+			case IncludeCopy, IncludeSymlink:
+				mode = IncludeMode(section)
+			default:
+				return nil, fmt.Errorf("line %d: unknown section [%s] (use [copy] or [symlink])", lineNo, section)
+			}
+			continue
+		}
+
+		patterns[mode] = append(patterns[mode], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return patterns, nil
+}
+
+// includeSection returns the name of a [section] header line
+func includeSection(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	name := strings.TrimSpace(line[1 : len(line)-1])
+	if name == "" {
+		return "", false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// Includes returns the patterns in the main worktree's IncludeFile, or nil if
+// there isn't one
+func (m *Manager) Includes() (map[IncludeMode][]string, error) {
+	f, err := os.Open(filepath.Join(m.repo.Path, IncludeFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IncludeFile, err)
+	}
+	defer f.Close()
+
+	patterns, err := ParseIncludeFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", IncludeFile, err)
+	}
+	return patterns, nil
+}
+
+// ApplyIncludes copies or symlinks the main worktree's ignored files that
+// match IncludeFile into a worktree. Paths the worktree already has are left
+// alone unless they match, or force is set. Returns nil if there is no
+// IncludeFile.
+func (m *Manager) ApplyIncludes(worktreePath string, force bool) ([]IncludedPath, error) {
+	patterns, err := m.Includes()
+	if err != nil || patterns == nil {
+		return nil, err
+	}
+
+	matches, err := m.matchIncludes(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var included []IncludedPath
+	for _, match := range matches {
+		src := filepath.Join(m.repo.Path, filepath.FromSlash(match.Path))
+		dst := filepath.Join(worktreePath, filepath.FromSlash(match.Path))
+
+		var status IncludeStatus
+		if match.Mode == IncludeSymlink {
+			status, err = linkInclude(src, dst, force)
+		} else {
+			status, err = copyInclude(src, dst, force)
+		}
+		if err != nil {
+			return included, fmt.Errorf("failed to include %s: %w", match.Path, err)
+		}
+		match.Status = status
+		included = append(included, match)
+	}
+	return included, nil
+}
+
+// matchIncludes lists the ignored paths in the main worktree that match the
+// patterns. git does the matching: the untracked paths matching each mode's
+// patterns are intersected with the paths .gitignore ignores. Directories
+// that match as a whole come back as one path.
+func (m *Manager) matchIncludes(patterns map[IncludeMode][]string) ([]IncludedPath, error) {
+	ignored, err := m.untrackedMatching(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Symlinks are matched last so they win over copies of the same path
+	modes := make(map[string]IncludeMode)
+	for _, mode := range []IncludeMode{IncludeCopy, IncludeSymlink} {
+		if len(patterns[mode]) == 0 {
+			continue
+		}
+		matched, err := m.untrackedMatching(patterns[mode])
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range matched {
+			for _, ig := range ignored {
+				switch {
+				case isUnder(p, ig):
+					modes[p] = mode
+				case isUnder(ig, p):
+					modes[ig] = mode
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(modes))
+	for p := range modes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var matches []IncludedPath
+	for _, p := range paths {
+		// A directory brought in as a whole covers everything inside it
+		if n := len(matches); n > 0 && isUnder(p, matches[n-1].Path) {
+			continue
+		}
+		matches = append(matches, IncludedPath{Path: p, Mode: modes[p]})
+	}
+	for i := range matches {
+		matches[i].Path = strings.TrimSuffix(matches[i].Path, "/")
+	}
+	return matches, nil
+}
+
+// untrackedMatching lists the untracked paths in the main worktree that match
+// patterns, or that are ignored by the repository's exclude files if
+// patterns is nil. Directories end in a slash.
+func (m *Manager) untrackedMatching(patterns []string) ([]string, error) {
+	args := []string{"ls-files", "-z", "--others", "--ignored", "--directory"}
+	if patterns == nil {
+		args = append(args, "--exclude-standard")
+	}
+	for _, p := range patterns {
+		args = append(args, "--exclude="+p)
+	}
+
+	output, err := m.git(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ignored files: %s", errOutput(err))
+	}
+	return splitNUL(output), nil
+}
+
+// isUnder reports whether path is dir or inside it, where directories end in
+// a slash
+func isUnder(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	return strings.HasSuffix(dir, "/") && strings.HasPrefix(path, dir)
+}
+
+// copyInclude copies a file or directory tree into a worktree. A directory is
+// skipped if any file in it was.
+func copyInclude(src, dst string, force bool) (IncludeStatus, error) {
+	status := IncludeUnchanged
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		s, err := copyIncludeFile(path, filepath.Join(dst, rel), force)
+		if err != nil {
+			return err
+		}
+		switch {
+		case s == IncludeSkipped:
+			status = s
+		case s != IncludeUnchanged && status == IncludeUnchanged:
+			status = s
+		}
+		return nil
+	})
+	return status, err
+}
+
+// copyIncludeFile copies one file, or recreates a symlink, unless dst already
+// has the same content
+func copyIncludeFile(src, dst string, force bool) (IncludeStatus, error) {
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		return "", err
+	}
+
+	status := IncludeCreated
+	if dstInfo, err := os.Lstat(dst); err == nil {
+		same, err := sameContent(src, dst, srcInfo, dstInfo)
+		if err != nil {
+			return "", err
+		}
+		if same {
+			return IncludeUnchanged, nil
+		}
+		if !force {
+			return IncludeSkipped, nil
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return "", err
+		}
+		status = IncludeUpdated
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if srcInfo.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return "", err
+		}
+		return status, os.Symlink(target, dst)
+	}
+	return status, copyFile(src, dst)
+}
+
+// sameContent reports whether two files, or two symlinks, are the same
+func sameContent(src, dst string, srcInfo, dstInfo os.FileInfo) (bool, error) {
+	if srcInfo.Mode().Type() != dstInfo.Mode().Type() {
+		return false, nil
+	}
+	if srcInfo.Mode()&os.ModeSymlink != 0 {
+		a, err := os.Readlink(src)
+		if err != nil {
+			return false, err
+		}
+		b, err := os.Readlink(dst)
+		return a == b, err
+	}
+	if srcInfo.Size() != dstInfo.Size() {
+		return false, nil
+	}
+
+	a, err := os.ReadFile(src)
+	if err != nil {
+		return false, err
+	}
+	b, err := os.ReadFile(dst)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(a, b), nil
+}
+
+// linkInclude symlinks dst to src, unless it already is
+func linkInclude(src, dst string, force bool) (IncludeStatus, error) {
+	status := IncludeCreated
+	if _, err := os.Lstat(dst); err == nil {
+		if target, err := os.Readlink(dst); err == nil && target == src {
+			return IncludeUnchanged, nil
+		}
+		if !force {
+			return IncludeSkipped, nil
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return "", err
+		}
+		status = IncludeUpdated
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	return status, os.Symlink(src, dst)
+}
@@ -0,0 +1,135 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIncludeFile(t *testing.T) {
+	input := `# Local files for new worktrees
+.env
+[Cc]erts/*.pem
+
+[symlink]
+node_modules/
+`
+	patterns, err := ParseIncludeFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseIncludeFile failed: %v", err)
+	}
+
+	want := map[IncludeMode][]string{
+		IncludeCopy:    {".env", "[Cc]erts/*.pem"},
+		IncludeSymlink: {"node_modules/"},
+	}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Errorf("patterns = %v, want %v", patterns, want)
+	}
+
+	if _, err := ParseIncludeFile(strings.NewReader("[links]\nnode_modules/\n")); err == nil {
+		t.Error("expected error for unknown section")
+	}
+}
+
+func TestApplyIncludes(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	write := func(file, content string) {
+		t.Helper()
+		path := filepath.Join(repoPath, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(".gitignore", ".env\nnode_modules/\ncerts/\n*.log\n")
+	write(".env", "PORT=3000\n")
+	write("node_modules/pkg/index.js", "module.exports = {}\n")
+	write("certs/dev.pem", "cert\n")
+	write("certs/dev.key", "key\n")
+	write("debug.log", "not included\n")
+	// Untracked but not ignored, so it isn't included even though it matches
+	write("notes.txt", "draft\n")
+	write(IncludeFile, ".env\ncerts/*.pem\nnotes.txt\n\n[symlink]\nnode_modules/\n")
+
+	result, err := mgr.AddWithOptions(AddOptions{Name: "feature", Branch: "feature"})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if result.IncludeErr != nil {
+		t.Fatalf("including files failed: %v", result.IncludeErr)
+	}
+
+	want := []IncludedPath{
+		{Path: ".env", Mode: IncludeCopy, Status: IncludeCreated},
+		{Path: "certs/dev.pem", Mode: IncludeCopy, Status: IncludeCreated},
+		{Path: "node_modules", Mode: IncludeSymlink, Status: IncludeCreated},
+	}
+	if !reflect.DeepEqual(result.Included, want) {
+		t.Errorf("Included = %+v, want %+v", result.Included, want)
+	}
+
+	wt := result.Path
+	if data, _ := os.ReadFile(filepath.Join(wt, ".env")); string(data) != "PORT=3000\n" {
+		t.Errorf(".env = %q", data)
+	}
+	if target, err := os.Readlink(filepath.Join(wt, "node_modules")); err != nil || target != filepath.Join(repoPath, "node_modules") {
+		t.Errorf("node_modules links to %q, %v", target, err)
+	}
+	for _, file := range []string{"certs/dev.key", "debug.log", "notes.txt"} {
+		if fileExists(filepath.Join(wt, file)) {
+			t.Errorf("%s should not be included", file)
+		}
+	}
+
+	// A worktree's own changes are kept unless forced
+	if err := os.WriteFile(filepath.Join(wt, ".env"), []byte("PORT=3001\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(wt, "certs", "dev.pem"))
+
+	included, err := mgr.ApplyIncludes(wt, false)
+	if err != nil {
+		t.Fatalf("ApplyIncludes failed: %v", err)
+	}
+	statuses := map[string]IncludeStatus{}
+	for _, inc := range included {
+		statuses[inc.Path] = inc.Status
+	}
+	wantStatuses := map[string]IncludeStatus{
+		".env":          IncludeSkipped,
+		"certs/dev.pem": IncludeCreated,
+		"node_modules":  IncludeUnchanged,
+	}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Errorf("statuses = %v, want %v", statuses, wantStatuses)
+	}
+
+	if _, err := mgr.ApplyIncludes(wt, true); err != nil {
+		t.Fatalf("ApplyIncludes with force failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt, ".env")); string(data) != "PORT=3000\n" {
+		t.Errorf(".env after force = %q", data)
+	}
+
+	// Opting out leaves the worktree without them
+	result, err = mgr.AddWithOptions(AddOptions{Name: "plain", Branch: "plain", SkipIncludes: true})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if len(result.Included) != 0 || fileExists(filepath.Join(result.Path, ".env")) {
+		t.Errorf("SkipIncludes still included %+v", result.Included)
+	}
+}
@@ -0,0 +1,180 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Preset layout names accepted in place of a worktree_dir template
+const (
+	LayoutSibling = "sibling"
+	LayoutNested  = "nested"
+	LayoutCentral = "central"
+)
+
+// DefaultWorktreeDir is used when no worktree_dir is configured
+const DefaultWorktreeDir = "../worktrees"
+
+// nestedDir is the directory inside the repository used by the nested layout
+const nestedDir = ".worktrees"
+
+// layoutPresets maps preset names to their templates
+var layoutPresets = map[string]string{
+	LayoutSibling: "../{repo}-worktrees/{name}",
+	LayoutNested:  nestedDir + "/{name}",
+	LayoutCentral: "~/worktrees/{repo}/{name}",
+}
+
+// placeholderRegex matches {placeholder} tokens in a layout template
+var placeholderRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// LayoutVars holds the values substituted into a layout template
+type LayoutVars struct {
+	Repo   string
+	Name   string
+	Branch string
+}
+
+// ResolveLayout returns the template for a preset name, or the value itself
+// if it is not a preset. An empty value resolves to the default layout.
+func ResolveLayout(layout string) string {
+	layout = strings.TrimSpace(layout)
+	if layout == "" {
+		layout = DefaultWorktreeDir
+	}
+	if tmpl, ok := layoutPresets[layout]; ok {
+		return tmpl
+	}
+	// A plain directory means "put worktrees in here, named after the worktree"
+	if !strings.Contains(layout, "{name}") && !strings.Contains(layout, "{branch}") {
+		layout = strings.TrimRight(layout, `/\`) + "/{name}"
+	}
+	return layout
+}
+
+// ValidateLayout checks that a worktree_dir value only uses known placeholders
+// and ends in {name}. Worktrees are identified by their directory name, so
+// any other final component would make them unreachable by name.
+func ValidateLayout(layout string) error {
+	tmpl := ResolveLayout(layout)
+	for _, match := range placeholderRegex.FindAllStringSubmatch(tmpl, -1) {
+		switch match[1] {
+		case "repo", "name", "branch":
+		default:
+			return fmt.Errorf("unknown placeholder {%s} (supported: {repo}, {name}, {branch})", match[1])
+		}
+	}
+
+	tmpl = strings.TrimRight(tmpl, `/\`)
+	if last := tmpl[strings.LastIndexAny(tmpl, `/\`)+1:]; last != "{name}" {
+		return fmt.Errorf("the last path component must be {name}, not '%s' (e.g. ~/worktrees/{repo}/{name})", last)
+	}
+	return nil
+}
+
+// ExpandLayout substitutes placeholders and ~ in a layout template and returns
+// an absolute path. Relative paths are resolved against baseDir.
+func ExpandLayout(layout string, vars LayoutVars, baseDir string) (string, error) {
+	if err := ValidateLayout(layout); err != nil {
+		return "", err
+	}
+	tmpl := ResolveLayout(layout)
+
+	path := strings.NewReplacer(
+		"{repo}", vars.Repo,
+		"{name}", vars.Name,
+		// Branches like feature/foo would otherwise create nested directories
+		"{branch}", strings.ReplaceAll(vars.Branch, "/", "-"),
+	).Replace(tmpl)
+
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// MoveDestination resolves the target of a move. A bare name keeps the
+// worktree in its current parent directory; anything that looks like a path
+// (contains a separator or starts with . or ~) is used as given, relative to
+// the current directory.
+func MoveDestination(oldPath, dest string) (string, error) {
+	if dest == "" {
+		return "", fmt.Errorf("destination cannot be empty")
+	}
+
+	if !strings.ContainsAny(dest, `/\`) && !strings.HasPrefix(dest, ".") && !strings.HasPrefix(dest, "~") {
+		return filepath.Join(filepath.Dir(oldPath), dest), nil
+	}
+
+	path, err := expandHome(dest)
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve destination: %w", err)
+	}
+	return path, nil
+}
+
+// isNestedPath reports whether path lies inside dir
+func isNestedPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ensureExcluded adds entry to the repository's info/exclude file so that
+// worktrees nested inside the main worktree don't show up as untracked.
+func ensureExcluded(gitDir, entry string) error {
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read exclude file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return fmt.Errorf("failed to create info directory: %w", err)
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += entry + "\n"
+
+	if err := os.WriteFile(excludePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to update exclude file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandLayout(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home dir: %v", err)
+	}
+
+	vars := LayoutVars{Repo: "app", Name: "feat", Branch: "feature/login"}
+	base := "/src/app"
+
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"", "/src/worktrees/feat"},
+		{"../worktrees", "/src/worktrees/feat"},
+		{"/tmp/wt/", "/tmp/wt/feat"},
+		{LayoutSibling, "/src/app-worktrees/feat"},
+		{LayoutNested, "/src/app/.worktrees/feat"},
+		{LayoutCentral, filepath.Join(home, "worktrees", "app", "feat")},
+		{"~/code/{repo}/{branch}/{name}", filepath.Join(home, "code", "app", "feature-login", "feat")},
+		{"../{repo}.wt/{name}/", "/src/app.wt/feat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			got, err := ExpandLayout(tt.layout, vars, base)
+			if err != nil {
+				t.Fatalf("ExpandLayout(%q) error = %v", tt.layout, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandLayout(%q) = %q, want %q", tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	if err := ValidateLayout("~/wt/{repo}/{name}"); err != nil {
+		t.Errorf("expected valid layout, got %v", err)
+	}
+	if err := ValidateLayout("../{project}/{name}"); err == nil {
+		t.Error("expected error for unknown placeholder")
+	}
+	for _, layout := range []string{"../{repo}-{name}", "~/code/{repo}/{branch}", "../{name}/src"} {
+		if err := ValidateLayout(layout); err == nil {
+			t.Errorf("expected error for %q, whose directory name isn't the worktree name", layout)
+		}
+	}
+}
+
+func TestLayoutsKeepWorktreeNames(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, layout := range []string{"", LayoutSibling, LayoutNested, LayoutCentral, "../wt/{repo}/{branch}/{name}"} {
+		t.Run(layout, func(t *testing.T) {
+			repoPath, cleanup, err := setupTestRepo(t, 0)
+			if err != nil {
+				t.Fatalf("Failed to setup test repo: %v", err)
+			}
+			defer cleanup()
+
+			mgr := NewManager(&Repository{Path: repoPath})
+			mgr.SetWorktreeDir(layout)
+			path, err := mgr.Add("feat", "feature/feat", "main")
+			if err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+			defer os.RemoveAll(path)
+
+			wt := findWorktree(t, mgr, "feat")
+			if wt.Path != path {
+				t.Errorf("worktree feat is at %s, want %s", wt.Path, path)
+			}
+			if err := mgr.Remove(wt.Name, false); err != nil {
+				t.Fatalf("Remove failed: %v", err)
+			}
+			if fileExists(path) {
+				t.Errorf("%s still exists", path)
+			}
+		})
+	}
+}
+
+func TestMoveDestination(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dest string
+		want string
+	}{
+		{"renamed", "/src/worktrees/renamed"},
+		{"/tmp/elsewhere", "/tmp/elsewhere"},
+		{"./local", filepath.Join(cwd, "local")},
+		{"../up", filepath.Join(filepath.Dir(cwd), "up")},
+		{"~/wt/feat", filepath.Join(home, "wt", "feat")},
+	}
+
+	for _, tt := range tests {
+		got, err := MoveDestination("/src/worktrees/feat", tt.dest)
+		if err != nil {
+			t.Fatalf("MoveDestination(%q) error = %v", tt.dest, err)
+		}
+		if got != tt.want {
+			t.Errorf("MoveDestination(%q) = %q, want %q", tt.dest, got, tt.want)
+		}
+	}
+
+	if _, err := MoveDestination("/src/worktrees/feat", ""); err == nil {
+		t.Error("expected error for empty destination")
+	}
+}
+
+func TestAddNestedLayout(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	mgr.SetWorktreeDir(LayoutNested)
+
+	path, err := mgr.Add("feat", "feat", "main")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	want := filepath.Join(repoPath, ".worktrees", "feat")
+	if path != want {
+		t.Errorf("Add path = %q, want %q", path, want)
+	}
+
+	exclude, err := os.ReadFile(filepath.Join(repoPath, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatalf("failed to read exclude: %v", err)
+	}
+	if !strings.Contains(string(exclude), "/.worktrees/") {
+		t.Errorf("expected /.worktrees/ in info/exclude, got:\n%s", exclude)
+	}
+
+	clean, err := mgr.IsClean(repoPath)
+	if err != nil {
+		t.Fatalf("IsClean failed: %v", err)
+	}
+	if !clean {
+		t.Error("expected main worktree to stay clean with nested worktree")
+	}
+}
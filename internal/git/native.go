@@ -0,0 +1,118 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// canListNatively reports whether the repository layout is one listNative
+// understands: a bare repository or a main worktree with a .git directory.
+// Separate git dirs only record the main worktree in core.worktree, so they
+// are left to git.
+func (m *Manager) canListNatively() bool {
+	if m.repo.IsBare {
+		return true
+	}
+	gitDir := m.repo.gitDir()
+	return filepath.Base(gitDir) == ".git" && samePath(filepath.Dir(gitDir), m.repo.Path)
+}
+
+// listNative builds the worktree list from the repository's administrative
+// files, producing the same result as git worktree list --porcelain without
+// forking git: the main worktree first, then linked worktrees sorted by path.
+func (m *Manager) listNative() ([]Worktree, error) {
+	commonDir := m.repo.gitDir()
+
+	main, err := m.nativeMainWorktree(commonDir)
+	if err != nil {
+		return nil, err
+	}
+	worktrees := []Worktree{main}
+
+	adminDirs, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var linked []Worktree
+	for _, entry := range adminDirs {
+		if !entry.IsDir() {
+			continue
+		}
+		wt, ok := readLinkedWorktree(commonDir, filepath.Join(commonDir, "worktrees", entry.Name()))
+		if ok {
+			linked = append(linked, wt)
+		}
+	}
+	sort.Slice(linked, func(i, j int) bool { return linked[i].Path < linked[j].Path })
+
+	return append(worktrees, linked...), nil
+}
+
+// nativeMainWorktree describes the main worktree, or the bare repository
+func (m *Manager) nativeMainWorktree(commonDir string) (Worktree, error) {
+	path := m.repo.Path
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+
+	wt := Worktree{Name: filepath.Base(path), Path: path, IsMain: true}
+	if m.repo.IsBare {
+		return wt, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(commonDir, "HEAD"))
+	if err != nil {
+		return wt, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	readNativeHead(&wt, strings.TrimSpace(string(data)), commonDir, commonDir)
+	return wt, nil
+}
+
+// readLinkedWorktree describes the linked worktree whose administrative files
+// live in adminDir. ok is false for directories git would ignore.
+func readLinkedWorktree(commonDir, adminDir string) (wt Worktree, ok bool) {
+	head, err := os.ReadFile(filepath.Join(adminDir, "HEAD"))
+	if err != nil {
+		return wt, false
+	}
+
+	if reason, err := os.ReadFile(filepath.Join(adminDir, "locked")); err == nil {
+		wt.Locked = true
+		wt.LockReason = strings.TrimSpace(string(reason))
+	}
+
+	// git skips entries without a readable gitdir file
+	data, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	dotGit := strings.TrimSpace(string(data))
+	if err != nil || dotGit == "" {
+		return wt, false
+	}
+	if !filepath.IsAbs(dotGit) {
+		dotGit = filepath.Join(adminDir, dotGit)
+	}
+	wt.Path = filepath.Dir(filepath.Clean(dotGit))
+	if _, err := os.Stat(dotGit); err != nil && !wt.Locked {
+		wt.Prunable = true
+		wt.PrunableReason = "gitdir file points to non-existent location"
+	}
+	wt.Name = filepath.Base(wt.Path)
+
+	readNativeHead(&wt, strings.TrimSpace(string(head)), adminDir, commonDir)
+	return wt, true
+}
+
+// readNativeHead fills in the branch or detached HEAD from a HEAD file
+func readNativeHead(wt *Worktree, head, gitDir, commonDir string) {
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		ref = strings.TrimSpace(ref)
+		wt.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		wt.Head = resolveRef(gitDir, commonDir, ref)
+		return
+	}
+	wt.Head = head
+	wt.Detached = true
+}
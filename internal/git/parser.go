@@ -2,6 +2,7 @@ package git
 
 import (
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -15,14 +16,53 @@ func parseWorktreeList(output string) ([]Worktree, error) {
 
 	// Split by double newline to get worktree blocks
 	blocks := strings.Split(output, "\n\n")
-	worktrees := make([]Worktree, 0, len(blocks))
+	records := make([][]string, 0, len(blocks))
+	for _, block := range blocks {
+		var fields []string
+		for _, line := range strings.Split(block, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				fields = append(fields, line)
+			}
+		}
+		records = append(records, fields)
+	}
 
+	return buildWorktreeList(records), nil
+}
+
+// parseWorktreeListZ parses the output of git worktree list --porcelain -z,
+// where fields are NUL-terminated and records end with an extra NUL. Unlike
+// the newline format this is safe for paths and lock reasons with newlines.
+func parseWorktreeListZ(output string) ([]Worktree, error) {
+	if strings.Trim(output, "\x00") == "" {
+		return []Worktree{}, nil
+	}
+
+	blocks := strings.Split(output, "\x00\x00")
+	records := make([][]string, 0, len(blocks))
 	for _, block := range blocks {
-		if block == "" {
+		var fields []string
+		for _, field := range strings.Split(block, "\x00") {
+			if field != "" {
+				fields = append(fields, field)
+			}
+		}
+		records = append(records, fields)
+	}
+
+	return buildWorktreeList(records), nil
+}
+
+// buildWorktreeList turns parsed records into worktrees
+func buildWorktreeList(records [][]string) []Worktree {
+	worktrees := make([]Worktree, 0, len(records))
+
+	for _, fields := range records {
+		if len(fields) == 0 {
 			continue
 		}
 
-		wt := parseWorktreeBlock(block)
+		wt := parseWorktreeFields(fields)
 		if wt.Path != "" {
 			worktrees = append(worktrees, wt)
 		}
@@ -33,34 +73,16 @@ func parseWorktreeList(output string) ([]Worktree, error) {
 		worktrees[0].IsMain = true
 	}
 
-	return worktrees, nil
+	return worktrees
 }
 
-// parseWorktreeBlock parses a single worktree block
-func parseWorktreeBlock(block string) Worktree {
+// parseWorktreeFields parses the fields of a single worktree record
+func parseWorktreeFields(fields []string) Worktree {
 	wt := Worktree{}
-	lines := strings.Split(block, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Handle special case: "bare" on its own line
-		if line == "bare" {
-			wt.IsMain = true
-			continue
-		}
-
-		// Split by first space only
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) != 2 {
-			continue
-		}
 
-		key := parts[0]
-		value := parts[1]
+	for _, field := range fields {
+		// Split by first space only; some attributes have no value
+		key, value, _ := strings.Cut(field, " ")
 
 		switch key {
 		case "worktree":
@@ -70,8 +92,88 @@ func parseWorktreeBlock(block string) Worktree {
 			wt.Head = value
 		case "branch":
 			wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			wt.IsMain = true
+		case "detached":
+			wt.Detached = true
+		case "locked":
+			wt.Locked = true
+			wt.LockReason = value
+		case "prunable":
+			wt.Prunable = true
+			wt.PrunableReason = value
 		}
 	}
 
 	return wt
 }
+
+// parseStatusV2 parses the output of git status --porcelain=v2 --branch
+func parseStatusV2(output string) *Status {
+	status := &Status{}
+	hasAheadBehind := false
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "# ") {
+			parts := strings.SplitN(line[2:], " ", 2)
+			if len(parts) != 2 {
+				continue
+			}
+
+			switch parts[0] {
+			case "branch.head":
+				if parts[1] == "(detached)" {
+					status.Detached = true
+				} else {
+					status.Branch = parts[1]
+				}
+			case "branch.upstream":
+				status.Upstream = parts[1]
+			case "branch.ab":
+				hasAheadBehind = true
+				for _, field := range strings.Fields(parts[1]) {
+					n, err := strconv.Atoi(field[1:])
+					if err != nil {
+						continue
+					}
+					if field[0] == '+' {
+						status.Ahead = n
+					} else if field[0] == '-' {
+						status.Behind = n
+					}
+				}
+			}
+			continue
+		}
+
+		switch line[0] {
+		case '1', '2':
+			// "1 XY ..." - X is the index (staged) state, Y the worktree state
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				status.Staged++
+			}
+			if line[3] != '.' {
+				status.Unstaged++
+			}
+		case 'u':
+			status.Conflicted++
+		case '?':
+			status.Untracked++
+		}
+	}
+
+	// An upstream without ahead/behind counts means the remote branch is gone
+	status.UpstreamGone = status.Upstream != "" && !hasAheadBehind
+
+	status.HasChanges = status.Staged+status.Unstaged+status.Untracked+status.Conflicted > 0
+	status.Clean = !status.HasChanges
+
+	return status
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSymrefDepth bounds how many symbolic refs resolveRef follows
+const maxSymrefDepth = 5
+
+// resolveRef returns the object ID a ref points at without forking git. It
+// follows symbolic refs and looks in loose refs before packed-refs.
+// Per-worktree refs such as HEAD are looked up in gitDir first, while
+// branches and other shared refs only live in commonDir. It returns "" when
+// the ref can't be resolved.
+func resolveRef(gitDir, commonDir, ref string) string {
+	for depth := 0; depth < maxSymrefDepth; depth++ {
+		value := readLooseRef(gitDir, commonDir, ref)
+		if value == "" {
+			return readPackedRef(commonDir, ref)
+		}
+		if !strings.HasPrefix(value, "ref:") {
+			return value
+		}
+		ref = strings.TrimSpace(strings.TrimPrefix(value, "ref:"))
+	}
+	return ""
+}
+
+// readLooseRef returns the contents of a loose ref file, or ""
+func readLooseRef(gitDir, commonDir, ref string) string {
+	dirs := []string{commonDir}
+	if isPerWorktreeRef(ref) {
+		dirs = []string{gitDir, commonDir}
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref)))
+		if err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
+
+// isPerWorktreeRef reports whether a ref lives in a worktree's own git
+// directory rather than the shared one
+func isPerWorktreeRef(ref string) bool {
+	return !strings.HasPrefix(ref, "refs/") ||
+		strings.HasPrefix(ref, "refs/worktree/") ||
+		strings.HasPrefix(ref, "refs/bisect/") ||
+		strings.HasPrefix(ref, "refs/rewritten/")
+}
+
+// readPackedRef looks a ref up in commonDir/packed-refs
+func readPackedRef(commonDir, ref string) string {
+	f, err := os.Open(filepath.Join(commonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Skip the header and peeled tag lines
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		oid, name, ok := strings.Cut(line, " ")
+		if ok && name == ref {
+			return oid
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Problem describes why a worktree is broken
+type Problem int
+
+const (
+	// ProblemNone means the worktree is healthy
+	ProblemNone Problem = iota
+	// ProblemMissing means the worktree directory no longer exists
+	ProblemMissing
+	// ProblemBrokenLink means the worktree's .git file and the repository's
+	// administrative files no longer point at each other, usually because
+	// the repository or the worktree was moved by hand
+	ProblemBrokenLink
+)
+
+// String returns a short description of the problem
+func (p Problem) String() string {
+	switch p {
+	case ProblemMissing:
+		return "missing"
+	case ProblemBrokenLink:
+		return "broken link"
+	default:
+		return "ok"
+	}
+}
+
+// Diagnose checks whether a worktree's directory and gitdir links are intact
+func (m *Manager) Diagnose(wt Worktree) Problem {
+	if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+		return ProblemMissing
+	}
+
+	// The main worktree of a bare repository has no .git to check
+	if wt.IsMain {
+		return ProblemNone
+	}
+
+	gitDir := worktreeGitDir(wt.Path)
+	if gitDir == "" {
+		return ProblemBrokenLink
+	}
+	if _, err := os.Stat(gitDir); err != nil {
+		return ProblemBrokenLink
+	}
+
+	// The administrative gitdir file must point back at this worktree
+	data, err := os.ReadFile(filepath.Join(gitDir, "gitdir"))
+	if err != nil {
+		return ProblemBrokenLink
+	}
+	backLink := strings.TrimSpace(string(data))
+	if !samePath(filepath.Dir(backLink), wt.Path) {
+		return ProblemBrokenLink
+	}
+
+	return ProblemNone
+}
+
+// Repair runs git worktree repair for the given worktree paths. With no paths
+// it repairs the links of every worktree git still knows about.
+func (m *Manager) Repair(paths ...string) error {
+	args := append([]string{"worktree", "repair"}, paths...)
+
+	if _, err := m.git(args...); err != nil {
+		return fmt.Errorf("failed to repair worktrees: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// samePath compares two paths after resolving symlinks where possible
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
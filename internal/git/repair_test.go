@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiagnoseAndRepair(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	for _, wt := range []*Worktree{findWorktree(t, mgr, "repo"), findWorktree(t, mgr, "wt-0")} {
+		if p := mgr.Diagnose(*wt); p != ProblemNone {
+			t.Errorf("Diagnose(%s) = %v, want ok", wt.Name, p)
+		}
+	}
+
+	// Deleting the directory by hand leaves a missing worktree
+	missing := findWorktree(t, mgr, "wt-1")
+	if err := os.RemoveAll(missing.Path); err != nil {
+		t.Fatal(err)
+	}
+	if p := mgr.Diagnose(*missing); p != ProblemMissing {
+		t.Errorf("Diagnose(missing) = %v, want missing", p)
+	}
+
+	// Corrupting the .git file breaks the link back to the repository
+	broken := findWorktree(t, mgr, "wt-0")
+	dotGit := filepath.Join(broken.Path, ".git")
+	if err := os.WriteFile(dotGit, []byte("gitdir: /nonexistent/worktrees/wt-0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p := mgr.Diagnose(*broken); p != ProblemBrokenLink {
+		t.Errorf("Diagnose(broken) = %v, want broken link", p)
+	}
+
+	if err := mgr.Repair(broken.Path); err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if p := mgr.Diagnose(*broken); p != ProblemNone {
+		t.Errorf("Diagnose after repair = %v, want ok", p)
+	}
+
+	if err := mgr.Prune(); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Errorf("expected 2 worktrees after prune, got %d", len(worktrees))
+	}
+}
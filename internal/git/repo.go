@@ -1,49 +1,100 @@
 package git
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
-// Repository represents a git repository
+// Repository represents a git repository.
+// Path is the main worktree (or the repository directory itself for bare
+// repositories) and GitDir is the common git directory shared by all worktrees.
 type Repository struct {
 	Path   string
 	GitDir string
+	IsBare bool
 }
 
-// FindRepo walks up directories to find a git repository
+// Name returns the repository name used in worktree layouts
+func (r *Repository) Name() string {
+	return strings.TrimSuffix(filepath.Base(r.Path), ".git")
+}
+
+// gitDir returns the repository's git directory
+func (r *Repository) gitDir() string {
+	if r.GitDir != "" {
+		return r.GitDir
+	}
+	return filepath.Join(r.Path, ".git")
+}
+
+// FindRepo locates the repository containing startPath. It works from the
+// main worktree, from any linked worktree and from bare repositories, and
+// always resolves to the common git directory so every worktree of a
+// repository maps to the same Repository.
 func FindRepo(startPath string) (*Repository, error) {
+	return FindRepoWithRunner(NewExecRunner(), startPath)
+}
+
+// FindRepoWithRunner is FindRepo with the git commands run through runner
+func FindRepoWithRunner(runner Runner, startPath string) (*Repository, error) {
 	path, err := filepath.Abs(startPath)
 	if err != nil {
 		return nil, err
 	}
 
-	for {
-		gitDir := filepath.Join(path, ".git")
-		if info, err := os.Stat(gitDir); err == nil {
-			if info.IsDir() {
-				return &Repository{
-					Path:   path,
-					GitDir: gitDir,
-				}, nil
-			}
-		}
+	ctx := context.Background()
+	output, err := runner.Run(ctx, Command{Dir: path, Args: []string{"rev-parse", "--git-common-dir"}})
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	commonDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(path, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+
+	// Ask from inside the common dir: a linked worktree of a bare repository
+	// reports itself as non-bare
+	output, err = runner.Run(ctx, Command{Dir: commonDir, Args: []string{"rev-parse", "--is-bare-repository"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect repository: %w", err)
+	}
+	isBare := strings.TrimSpace(string(output)) == "true"
+
+	repo := &Repository{
+		Path:   commonDir,
+		GitDir: commonDir,
+		IsBare: isBare,
+	}
+	if isBare {
+		return repo, nil
+	}
 
-		parent := filepath.Dir(path)
-		if parent == path {
-			return nil, fmt.Errorf("not a git repository")
-		}
-		path = parent
+	if filepath.Base(commonDir) == ".git" {
+		repo.Path = filepath.Dir(commonDir)
+		return repo, nil
+	}
+
+	// Separate git dir: the first worktree listed is always the main one
+	mgr := NewManager(repo)
+	mgr.SetRunner(runner)
+	worktrees, err := mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(worktrees) > 0 {
+		repo.Path = worktrees[0].Path
 	}
+	return repo, nil
 }
 
 // GetRootPath returns the repository root using git command
 func GetRootPath() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	output, err := NewExecRunner().Run(context.Background(), Command{Args: []string{"rev-parse", "--show-toplevel"}})
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository")
 	}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pullRequestRefs are the refs hosts publish pull request heads under:
+// GitHub's refs/pull and GitLab's refs/merge-requests
+var pullRequestRefs = []string{
+	"refs/pull/%d/head",
+	"refs/merge-requests/%d/head",
+}
+
+// PullRequestBranch returns the local branch a pull request is checked out
+// on, e.g. "pr-42"
+func PullRequestBranch(number int) string {
+	return fmt.Sprintf("pr-%d", number)
+}
+
+// ReviewRemote picks the remote pull requests are fetched from: the given
+// remote, else the preferred remote, else origin, else the only remote
+func (m *Manager) ReviewRemote(remote string) (string, error) {
+	if remote != "" {
+		return remote, nil
+	}
+	if m.preferredRemote != "" {
+		return m.preferredRemote, nil
+	}
+
+	remotes, err := m.Remotes()
+	if err != nil {
+		return "", err
+	}
+	for _, r := range remotes {
+		if r == "origin" {
+			return r, nil
+		}
+	}
+	switch len(remotes) {
+	case 0:
+		return "", fmt.Errorf("no remotes configured")
+	case 1:
+		return remotes[0], nil
+	}
+	return "", fmt.Errorf("several remotes configured (%s); choose one with --remote", strings.Join(remotes, ", "))
+}
+
+// FetchPullRequest fetches the head of a pull request from remote into a
+// local branch and returns the remote ref it came from. An existing branch
+// is only fast-forwarded, so commits made while reviewing are never lost.
+func (m *Manager) FetchPullRequest(remote string, number int, branch string) (string, error) {
+	for _, pattern := range pullRequestRefs {
+		ref := fmt.Sprintf(pattern, number)
+		_, err := m.gitWrite(m.repo.Path, "fetch", "--no-tags", remote, ref+":refs/heads/"+branch)
+		if err == nil {
+			return ref, nil
+		}
+
+		stderr := errOutput(err)
+		switch {
+		case strings.Contains(stderr, "couldn't find remote ref"):
+			continue
+		case strings.Contains(stderr, "non-fast-forward"):
+			return "", fmt.Errorf("branch '%s' has diverged from pull request #%d; rename or delete it first", branch, number)
+		}
+		return "", fmt.Errorf("failed to fetch pull request #%d: %s", number, stderr)
+	}
+
+	return "", fmt.Errorf("pull request #%d not found on remote '%s' (looked for refs/pull/%d/head and refs/merge-requests/%d/head)",
+		number, remote, number, number)
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFetchPullRequest(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	commit := func(file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repoPath, "add", file)
+		runGit(t, repoPath, "commit", "-q", "-m", file)
+	}
+
+	// A bare remote publishing one GitHub and one GitLab style head
+	remotePath := filepath.Join(filepath.Dir(repoPath), "remote.git")
+	runGit(t, filepath.Dir(repoPath), "init", "-q", "--bare", remotePath)
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+	runGit(t, repoPath, "push", "-q", "origin", "main")
+
+	runGit(t, repoPath, "checkout", "-q", "-b", "contributor")
+	commit("feature.txt")
+	runGit(t, repoPath, "push", "-q", "origin", "HEAD:refs/pull/7/head")
+	commit("more.txt")
+	runGit(t, repoPath, "push", "-q", "origin", "HEAD:refs/merge-requests/8/head")
+	runGit(t, repoPath, "checkout", "-q", "main")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	remote, err := mgr.ReviewRemote("")
+	if err != nil || remote != "origin" {
+		t.Fatalf("ReviewRemote = %q, %v; want origin", remote, err)
+	}
+
+	ref, err := mgr.FetchPullRequest("origin", 7, PullRequestBranch(7))
+	if err != nil {
+		t.Fatalf("FetchPullRequest(7) failed: %v", err)
+	}
+	if ref != "refs/pull/7/head" {
+		t.Errorf("ref = %q, want refs/pull/7/head", ref)
+	}
+
+	ref, err = mgr.FetchPullRequest("origin", 8, PullRequestBranch(8))
+	if err != nil {
+		t.Fatalf("FetchPullRequest(8) failed: %v", err)
+	}
+	if ref != "refs/merge-requests/8/head" {
+		t.Errorf("ref = %q, want refs/merge-requests/8/head", ref)
+	}
+	if got, want := strings.TrimSpace(runGit(t, repoPath, "rev-parse", "pr-8")), strings.TrimSpace(runGit(t, repoPath, "rev-parse", "contributor")); got != want {
+		t.Errorf("pr-8 = %s, want %s", got, want)
+	}
+
+	if _, err := mgr.FetchPullRequest("origin", 9, PullRequestBranch(9)); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	// The fetched branch is checked out like any other local branch
+	result, err := mgr.AddWithOptions(AddOptions{Name: "pr-7", Branch: "pr-7"})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if !fileExists(filepath.Join(result.Path, "feature.txt")) {
+		t.Error("expected the pull request's files in the worktree")
+	}
+
+	stat, err := mgr.DiffStat("main", "pr-7")
+	if err != nil {
+		t.Fatalf("DiffStat failed: %v", err)
+	}
+	if !strings.Contains(stat, "feature.txt") || !strings.Contains(stat, "1 file changed") {
+		t.Errorf("unexpected diffstat:\n%s", stat)
+	}
+
+	// Review commits are never overwritten by a later fetch
+	runGit(t, repoPath, "branch", "-f", "pr-8", "main")
+	runGit(t, repoPath, "checkout", "-q", "pr-8")
+	commit("local.txt")
+	runGit(t, repoPath, "checkout", "-q", "main")
+	if _, err := mgr.FetchPullRequest("origin", 8, PullRequestBranch(8)); err == nil || !strings.Contains(err.Error(), "diverged") {
+		t.Errorf("expected diverged error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/darkLord19/wtx/internal/logger"
+)
+
+// Command is a single git invocation
+type Command struct {
+	// Dir is the directory git runs in
+	Dir string
+	// Args are the arguments after "git"
+	Args []string
+	// Env holds extra KEY=VALUE entries added to the environment
+	Env []string
+	// Stdin is fed to git's standard input, if set
+	Stdin io.Reader
+	// NoTimeout exempts the command from the runner's per-call timeout.
+	// Commands that write to the working tree set it so they are never
+	// killed halfway through.
+	NoTimeout bool
+	// Stderr, if set, receives git's standard error as it is written, such
+	// as progress output, instead of it being kept for the CommandError
+	Stderr io.Writer
+}
+
+// Runner executes git commands. Manager uses it for every git invocation so
+// callers can add tracing or tests can substitute a fake.
+type Runner interface {
+	// Run executes the command and returns its standard output. A failed
+	// command returns a *CommandError.
+	Run(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// CommandError describes a git command that failed or could not be started
+type CommandError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	msg := e.Stderr
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), msg)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExecRunner runs git as a subprocess
+type ExecRunner struct {
+	// Timeout bounds each command unless it sets NoTimeout; zero disables it
+	Timeout time.Duration
+	// Env holds KEY=VALUE entries added to every command
+	Env []string
+	// Trace logs every invocation and its duration
+	Trace bool
+}
+
+// NewExecRunner returns a runner that never takes optional locks, so
+// read-only commands like status don't contend with the user's own git
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{Env: []string{"GIT_OPTIONAL_LOCKS=0"}}
+}
+
+// Run implements Runner
+func (r *ExecRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r.Timeout > 0 && !c.NoTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "git", c.Args...)
+	cmd.Dir = c.Dir
+	if len(r.Env) > 0 || len(c.Env) > 0 {
+		cmd.Env = append(append(os.Environ(), r.Env...), c.Env...)
+	}
+	if c.Stdin != nil {
+		cmd.Stdin = c.Stdin
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if c.Stderr != nil {
+		cmd.Stderr = c.Stderr
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	if r.Trace {
+		logger.Debug("git %s (dir=%s) took %s, err=%v", strings.Join(c.Args, " "), c.Dir, time.Since(start).Round(time.Millisecond), err)
+	}
+
+	if err != nil {
+		cmdErr := &CommandError{
+			Args:     c.Args,
+			ExitCode: -1,
+			Stderr:   strings.TrimSpace(stderr.String()),
+			Err:      err,
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cmdErr.ExitCode = exitErr.ExitCode()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			cmdErr.Err = ctxErr
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				cmdErr.Stderr = fmt.Sprintf("timed out after %s", r.Timeout)
+			}
+		}
+		return stdout.Bytes(), cmdErr
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// exitCode returns the exit code of a failed git command, or -1
+func exitCode(err error) int {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.ExitCode
+	}
+	return -1
+}
+
+// errOutput returns what git printed for a failed command, falling back to the
+// error itself
+func errOutput(err error) string {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Stderr != "" {
+		return cmdErr.Stderr
+	}
+	return err.Error()
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRunner answers git commands from a table keyed on the joined arguments
+// and records every call
+type fakeRunner struct {
+	responses map[string]fakeResponse
+	calls     []Command
+}
+
+type fakeResponse struct {
+	output   string
+	exitCode int
+	stderr   string
+}
+
+func (f *fakeRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	f.calls = append(f.calls, c)
+	resp, ok := f.responses[strings.Join(c.Args, " ")]
+	if !ok {
+		return nil, &CommandError{Args: c.Args, ExitCode: 1, Stderr: "unexpected command", Err: errors.New("exit status 1")}
+	}
+	if resp.exitCode != 0 {
+		return []byte(resp.output), &CommandError{Args: c.Args, ExitCode: resp.exitCode, Stderr: resp.stderr, Err: errors.New("exit status")}
+	}
+	return []byte(resp.output), nil
+}
+
+func TestManagerUsesRunner(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"worktree list --porcelain -z": {
+			output: "worktree /repo\x00HEAD abc123\x00branch refs/heads/main\x00\x00" +
+				"worktree /repo-feature\x00HEAD def456\x00branch refs/heads/feature\x00locked busy\x00\x00",
+		},
+		"config --local --unset wtx.defaultBranch": {exitCode: 5},
+		"worktree unlock feature":                  {exitCode: 128, stderr: "fatal: 'feature' is not locked"},
+	}}
+
+	mgr := NewManager(&Repository{Path: "/repo"})
+	mgr.SetRunner(runner)
+
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("Expected 2 worktrees, got %d", len(worktrees))
+	}
+	if wt := worktrees[1]; wt.Branch != "feature" || !wt.Locked || wt.LockReason != "busy" {
+		t.Errorf("Unexpected worktree: %+v", wt)
+	}
+	if dir := runner.calls[0].Dir; dir != "/repo" {
+		t.Errorf("List ran in %q, want /repo", dir)
+	}
+
+	// Exit code 5 from an unset is not an error
+	if err := mgr.SetDefaultBranch(""); err != nil {
+		t.Errorf("SetDefaultBranch(\"\") failed: %v", err)
+	}
+
+	// Other failures surface git's message
+	err = mgr.Unlock("feature")
+	if err == nil || !strings.Contains(err.Error(), "is not locked") {
+		t.Errorf("Unlock error = %v, want git's message", err)
+	}
+}
+
+func TestExecRunnerTimeout(t *testing.T) {
+	runner := NewExecRunner()
+	runner.Timeout = time.Nanosecond
+
+	_, err := runner.Run(context.Background(), Command{Args: []string{"version"}})
+	if err == nil {
+		t.Fatal("Expected the command to time out")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	// NoTimeout commands ignore the runner's timeout
+	output, err := runner.Run(context.Background(), Command{Args: []string{"version"}, NoTimeout: true})
+	if err != nil {
+		t.Fatalf("NoTimeout command failed: %v", err)
+	}
+	if !strings.HasPrefix(string(output), "git version") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
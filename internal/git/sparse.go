@@ -0,0 +1,170 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SparseProfileFile is the repository file defining sparse-checkout profiles.
+// Each profile is a [name] header followed by one cone-mode directory per
+// line; blank lines and lines starting with # are ignored:
+//
+//	[frontend]
+//	apps/web
+//	packages/ui
+const SparseProfileFile = ".wtxsparse"
+
+// ParseSparseProfiles reads profiles in the SparseProfileFile format
+func ParseSparseProfiles(r io.Reader) (map[string][]string, error) {
+	profiles := make(map[string][]string)
+	current := ""
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated profile header", lineNo)
+			}
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if current == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", lineNo)
+			}
+			if _, ok := profiles[current]; !ok {
+				profiles[current] = []string{}
+			}
+			continue
+		}
+
+		if current == "" {
+			return nil, fmt.Errorf("line %d: path outside of a [profile] section", lineNo)
+		}
+		profiles[current] = append(profiles[current], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// SparseProfiles returns the available sparse-checkout profiles: the
+// configured ones plus those in the main worktree's SparseProfileFile, which
+// take precedence
+func (m *Manager) SparseProfiles(configured map[string][]string) (map[string][]string, error) {
+	profiles := make(map[string][]string, len(configured))
+	for name, paths := range configured {
+		profiles[name] = paths
+	}
+
+	f, err := os.Open(filepath.Join(m.repo.Path, SparseProfileFile))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SparseProfileFile, err)
+	}
+	defer f.Close()
+
+	fromRepo, err := ParseSparseProfiles(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SparseProfileFile, err)
+	}
+	for name, paths := range fromRepo {
+		profiles[name] = paths
+	}
+	return profiles, nil
+}
+
+// ProfileNames returns profile names in sorted order
+func ProfileNames(profiles map[string][]string) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SparsePaths returns the cone-mode directories checked out in a worktree,
+// or nil if it isn't sparse
+func (m *Manager) SparsePaths(worktreePath string) ([]string, error) {
+	output, err := m.gitIn(worktreePath, "config", "--get", "core.sparseCheckout")
+	if err != nil || strings.TrimSpace(string(output)) != "true" {
+		return nil, nil
+	}
+
+	output, err = m.gitIn(worktreePath, "sparse-checkout", "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sparse-checkout paths: %s", errOutput(err))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// SetSparsePaths restricts a worktree to the given cone-mode directories and
+// updates its working tree to match
+func (m *Manager) SetSparsePaths(worktreePath string, paths []string) error {
+	args := append([]string{"sparse-checkout", "set", "--cone", "--"}, paths...)
+	if _, err := m.gitWrite(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to set sparse-checkout paths: %s", errOutput(err))
+	}
+	return nil
+}
+
+// AddSparsePaths adds cone-mode directories to a sparse worktree
+func (m *Manager) AddSparsePaths(worktreePath string, paths []string) error {
+	args := append([]string{"sparse-checkout", "add", "--"}, paths...)
+	if _, err := m.gitWrite(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to add sparse-checkout paths: %s", errOutput(err))
+	}
+	return nil
+}
+
+// RemoveSparsePaths drops cone-mode directories from a sparse worktree. git
+// has no sparse-checkout remove, so the remaining paths are set again.
+func (m *Manager) RemoveSparsePaths(worktreePath string, paths []string) error {
+	current, err := m.SparsePaths(worktreePath)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("worktree is not a sparse checkout")
+	}
+
+	drop := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		drop[normalizeSparsePath(p)] = true
+	}
+
+	var remaining []string
+	removed := 0
+	for _, p := range current {
+		if drop[normalizeSparsePath(p)] {
+			removed++
+			continue
+		}
+		remaining = append(remaining, p)
+	}
+	if removed == 0 {
+		return fmt.Errorf("none of the paths are part of the sparse checkout")
+	}
+
+	return m.SetSparsePaths(worktreePath, remaining)
+}
+
+// normalizeSparsePath makes "apps/web/", "./apps/web" and "apps/web" compare
+// equal
+func normalizeSparsePath(p string) string {
+	return strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSparseProfiles(t *testing.T) {
+	input := `# Profiles for the monorepo
+[frontend]
+apps/web
+packages/ui
+
+[empty]
+`
+	profiles, err := ParseSparseProfiles(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSparseProfiles failed: %v", err)
+	}
+
+	want := map[string][]string{
+		"frontend": {"apps/web", "packages/ui"},
+		"empty":    {},
+	}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("profiles = %v, want %v", profiles, want)
+	}
+
+	for _, bad := range []string{"apps/web\n", "[frontend\n", "[]\n"} {
+		if _, err := ParseSparseProfiles(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestAddSparse(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	for _, dir := range []string{"web", "api", "docs"} {
+		if err := os.MkdirAll(filepath.Join(repoPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoPath, dir, "file.txt"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, SparseProfileFile), []byte("[web]\nweb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repoPath, "add", ".")
+	runGit(t, repoPath, "commit", "-m", "Add directories")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	// The repo file overrides configured profiles of the same name
+	profiles, err := mgr.SparseProfiles(map[string][]string{"web": {"docs"}, "api": {"api"}})
+	if err != nil {
+		t.Fatalf("SparseProfiles failed: %v", err)
+	}
+	if got := profiles["web"]; !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("web profile = %v, want [web]", got)
+	}
+	if _, ok := profiles["api"]; !ok {
+		t.Error("expected configured api profile")
+	}
+
+	result, err := mgr.AddWithOptions(AddOptions{Name: "sparse", Branch: "sparse", SparsePaths: profiles["web"]})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+
+	exists := func(dir string) bool {
+		_, err := os.Stat(filepath.Join(result.Path, dir))
+		return err == nil
+	}
+	if !exists("web") || !exists("README.md") || exists("api") || exists("docs") {
+		t.Errorf("unexpected checkout contents: web=%v README=%v api=%v docs=%v",
+			exists("web"), exists("README.md"), exists("api"), exists("docs"))
+	}
+
+	// Sparse checkout is per worktree; the main worktree stays complete
+	if !fileExists(filepath.Join(repoPath, "api")) {
+		t.Error("expected main worktree to keep all directories")
+	}
+	if status, err := mgr.GetStatus(result.Path); err != nil || !status.Clean {
+		t.Errorf("expected a clean sparse worktree, got %+v, %v", status, err)
+	}
+
+	if err := mgr.AddSparsePaths(result.Path, []string{"api"}); err != nil {
+		t.Fatalf("AddSparsePaths failed: %v", err)
+	}
+	if err := mgr.RemoveSparsePaths(result.Path, []string{"web/"}); err != nil {
+		t.Fatalf("RemoveSparsePaths failed: %v", err)
+	}
+	paths, err := mgr.SparsePaths(result.Path)
+	if err != nil {
+		t.Fatalf("SparsePaths failed: %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"api"}) {
+		t.Errorf("sparse paths = %v, want [api]", paths)
+	}
+	if exists("web") || !exists("api") {
+		t.Error("expected working tree to follow the sparse paths")
+	}
+
+	// A failed sparse checkout leaves neither the worktree nor a new
+	// branch behind, but never deletes an existing branch
+	runGit(t, repoPath, "branch", "existing")
+	for _, branch := range []string{"broken", "existing"} {
+		path, err := mgr.WorktreePath("broken-"+branch, branch)
+		if err != nil {
+			t.Fatalf("WorktreePath failed: %v", err)
+		}
+		result, err := mgr.AddWithOptions(AddOptions{Name: "broken-" + branch, Branch: branch, SparsePaths: []string{"../outside"}})
+		if err == nil {
+			t.Fatalf("AddWithOptions(%s) with an invalid sparse path succeeded: %+v", branch, result)
+		}
+		if fileExists(path) {
+			t.Errorf("%s: the failed worktree was left behind", branch)
+		}
+		if exists := mgr.localBranchExists(branch); exists != (branch == "existing") {
+			t.Errorf("%s: branch exists = %v after a failed sparse checkout", branch, exists)
+		}
+	}
+
+	// A full checkout has no sparse paths
+	if paths, err := mgr.SparsePaths(repoPath); err != nil || paths != nil {
+		t.Errorf("SparsePaths(main) = %v, %v; want nil", paths, err)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
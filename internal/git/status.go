@@ -1,11 +1,25 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"strconv"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+)
+
+// Operation is an in-progress git operation in a worktree
+type Operation string
+
+const (
+	OperationNone       Operation = ""
+	OperationRebase     Operation = "rebase"
+	OperationMerge      Operation = "merge"
+	OperationCherryPick Operation = "cherry-pick"
+	OperationBisect     Operation = "bisect"
 )
 
 // Status represents the git status of a worktree
@@ -14,44 +28,124 @@ type Status struct {
 	Ahead      int
 	Behind     int
 	HasChanges bool
+
+	// Change counts
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+
+	// Branch and upstream
+	Branch       string
+	Upstream     string
+	UpstreamGone bool
+	Detached     bool
+
+	// Operation is set while a rebase, merge, cherry-pick or bisect is in progress
+	Operation Operation
 }
 
-// GetStatus returns the git status for a worktree
-func (m *Manager) GetStatus(worktreePath string) (*Status, error) {
-	status := &Status{Clean: true}
+// State returns a short label describing the worktree state, giving
+// in-progress operations and conflicts precedence over plain changes
+func (s *Status) State() string {
+	switch s.Operation {
+	case OperationRebase:
+		return "rebasing"
+	case OperationMerge:
+		return "merging"
+	case OperationCherryPick:
+		return "cherry-picking"
+	case OperationBisect:
+		return "bisecting"
+	}
+	if s.Conflicted > 0 {
+		return "conflicted"
+	}
+	if !s.Clean {
+		return "dirty"
+	}
+	return "clean"
+}
+
+// ChangeSummary returns compact change counts such as "+2 ~1 ?3 !1"
+// (staged, unstaged, untracked, conflicted), or "" when there are none
+func (s *Status) ChangeSummary() string {
+	var parts []string
+	if s.Staged > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", s.Staged))
+	}
+	if s.Unstaged > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", s.Unstaged))
+	}
+	if s.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("?%d", s.Untracked))
+	}
+	if s.Conflicted > 0 {
+		parts = append(parts, fmt.Sprintf("!%d", s.Conflicted))
+	}
+	return strings.Join(parts, " ")
+}
 
-	// Check for uncommitted changes
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = worktreePath
-	output, err := cmd.Output()
+// GetStatus returns the git status for a worktree using a single
+// git status --porcelain=v2 --branch call
+func (m *Manager) GetStatus(worktreePath string) (*Status, error) {
+	output, err := m.gitIn(worktreePath, "status", "--porcelain=v2", "--branch")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
 
-	if len(strings.TrimSpace(string(output))) > 0 {
-		status.Clean = false
-		status.HasChanges = true
+	status := parseStatusV2(string(output))
+	status.Operation = detectOperation(worktreeGitDir(worktreePath))
+
+	return status, nil
+}
+
+// worktreeGitDir returns the per-worktree git directory without forking git.
+// In linked worktrees .git is a file containing "gitdir: <path>".
+func worktreeGitDir(worktreePath string) string {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
 	}
 
-	// Check ahead/behind
-	cmd = exec.Command("git", "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
-	cmd.Dir = worktreePath
-	output, err = cmd.Output()
-	if err == nil {
-		// Parse "X\tY" format
-		parts := strings.Fields(string(output))
-		if len(parts) == 2 {
-			if ahead, err := strconv.Atoi(parts[0]); err == nil {
-				status.Ahead = ahead
-			}
-			if behind, err := strconv.Atoi(parts[1]); err == nil {
-				status.Behind = behind
-			}
-		}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+	if gitDir != "" && !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return gitDir
+}
+
+// detectOperation checks the state files git leaves in a worktree's git
+// directory while an operation is in progress
+func detectOperation(gitDir string) Operation {
+	if gitDir == "" {
+		return OperationNone
 	}
-	// Ignore error if no upstream is set
 
-	return status, nil
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply") && !exists(filepath.Join("rebase-apply", "applying")):
+		return OperationRebase
+	case exists("MERGE_HEAD"):
+		return OperationMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OperationCherryPick
+	case exists("BISECT_LOG"):
+		return OperationBisect
+	}
+	return OperationNone
 }
 
 // IsClean checks if a worktree has no uncommitted changes
@@ -63,52 +157,96 @@ func (m *Manager) IsClean(worktreePath string) (bool, error) {
 	return status.Clean, nil
 }
 
-// GetStatuses returns the status for multiple worktrees concurrently.
-// It uses a bounded worker pool to limit resource usage.
-// Returns a map of worktree path -> Status.
-func (m *Manager) GetStatuses(worktrees []Worktree) map[string]*Status {
-	results := make(map[string]*Status)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+// DefaultStatusWorkers is the default number of concurrent git status calls
+const DefaultStatusWorkers = 10
 
-	// Worker pool size
-	maxWorkers := 10
-	if len(worktrees) < maxWorkers {
-		maxWorkers = len(worktrees)
+// SetStatusWorkers sets how many git status calls run concurrently. Values
+// below 1 restore the default.
+func (m *Manager) SetStatusWorkers(n int) {
+	if n < 1 {
+		n = DefaultStatusWorkers
 	}
-	if maxWorkers == 0 {
-		return results
+	m.statusWorkers = n
+}
+
+// StatusResult is the outcome of a status check for one worktree
+type StatusResult struct {
+	Worktree Worktree
+	Status   *Status
+	Err      error
+}
+
+// TimedOut reports whether the status check was cut off by a timeout
+func (r StatusResult) TimedOut() bool {
+	return errors.Is(r.Err, context.DeadlineExceeded)
+}
+
+// StreamStatuses checks worktrees on a bounded worker pool and delivers each
+// result as soon as it completes. timeout bounds each worktree's check; zero
+// leaves only the runner's timeout. The channel is closed once every worktree
+// has been reported or ctx is done.
+func (m *Manager) StreamStatuses(ctx context.Context, worktrees []Worktree, timeout time.Duration) <-chan StatusResult {
+	results := make(chan StatusResult)
+
+	workers := m.statusWorkers
+	if workers < 1 {
+		workers = DefaultStatusWorkers
+	}
+	if len(worktrees) < workers {
+		workers = len(worktrees)
 	}
 
 	workChan := make(chan Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		workChan <- wt
+	}
+	close(workChan)
 
-	// Start workers
-	for i := 0; i < maxWorkers; i++ {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for wt := range workChan {
-				status, err := m.GetStatus(wt.Path)
-				if err != nil {
-					// Mimic original behavior: if error, result is nil (missing from map)
-					continue
+				result := m.statusWithTimeout(ctx, wt, timeout)
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
 				}
-
-				mu.Lock()
-				results[wt.Path] = status
-				mu.Unlock()
 			}
 		}()
 	}
 
-	// Enqueue work
-	for _, wt := range worktrees {
-		workChan <- wt
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// statusWithTimeout checks one worktree, cancelling git after timeout
+func (m *Manager) statusWithTimeout(ctx context.Context, wt Worktree, timeout time.Duration) StatusResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	close(workChan)
 
-	// Wait for completion
-	wg.Wait()
+	status, err := m.WithContext(ctx).GetStatus(wt.Path)
+	return StatusResult{Worktree: wt, Status: status, Err: err}
+}
 
+// GetStatuses returns the status for multiple worktrees concurrently.
+// Returns a map of worktree path -> Status; worktrees whose status couldn't
+// be read are missing from the map.
+func (m *Manager) GetStatuses(worktrees []Worktree) map[string]*Status {
+	results := make(map[string]*Status)
+	for result := range m.StreamStatuses(m.ctx, worktrees, 0) {
+		if result.Err == nil {
+			results[result.Worktree.Path] = result.Status
+		}
+	}
 	return results
 }
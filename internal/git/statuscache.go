@@ -0,0 +1,176 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// statusCacheFile is the name of the status cache inside the git directory,
+// next to the metadata store
+const statusCacheFile = "wtx-status-cache.json"
+
+// statusCacheMaxAge is how long a cached status is trusted while its key
+// still matches. Edits to tracked files and new untracked files change
+// neither the index nor HEAD (git status runs with GIT_OPTIONAL_LOCKS=0 and
+// never refreshes the index), so this bounds how long they can go unnoticed.
+const statusCacheMaxAge = 5 * time.Minute
+
+// statusKey captures the state a cached status was computed from: the
+// index, HEAD and upstream
+type statusKey struct {
+	IndexModTime int64  `json:"index_mtime"`
+	IndexSize    int64  `json:"index_size"`
+	Head         string `json:"head"`
+	Upstream     string `json:"upstream,omitempty"`
+	UpstreamOID  string `json:"upstream_oid,omitempty"`
+}
+
+type statusCacheEntry struct {
+	Key       statusKey `json:"key"`
+	Status    *Status   `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// StatusCache persists worktree statuses between runs so worktrees whose
+// index, HEAD and upstream haven't changed since they were recently checked
+// don't need a git status call. It is safe for concurrent use.
+type StatusCache struct {
+	path      string
+	commonDir string
+
+	mu      sync.Mutex
+	entries map[string]*statusCacheEntry
+}
+
+// LoadStatusCache reads the status cache stored in the given common git
+// directory. A missing or unreadable cache yields an empty one.
+func LoadStatusCache(gitDir string) *StatusCache {
+	c := &StatusCache{
+		path:      filepath.Join(gitDir, statusCacheFile),
+		commonDir: gitDir,
+		entries:   make(map[string]*statusCacheEntry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil || c.entries == nil {
+		c.entries = make(map[string]*statusCacheEntry)
+	}
+	return c
+}
+
+// Lookup returns the last status recorded for a worktree, or nil. fresh
+// reports whether it can be used without running git status: the index,
+// HEAD and upstream are unchanged and it was checked within
+// statusCacheMaxAge. Otherwise it is only a placeholder to show while a new
+// status is computed.
+func (c *StatusCache) Lookup(wt Worktree) (status *Status, fresh bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[wt.Path]
+	c.mu.Unlock()
+	if !ok || entry.Status == nil {
+		return nil, false
+	}
+
+	if time.Since(entry.CheckedAt) > statusCacheMaxAge {
+		return entry.Status, false
+	}
+	key, ok := c.key(wt, entry.Key.Upstream)
+	return entry.Status, ok && key == entry.Key
+}
+
+// Put records a freshly computed status
+func (c *StatusCache) Put(wt Worktree, status *Status) {
+	key, ok := c.key(wt, status.Upstream)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[wt.Path] = &statusCacheEntry{
+		Key:       key,
+		Status:    status,
+		CheckedAt: time.Now(),
+	}
+}
+
+// Retain drops entries for worktrees that are no longer listed
+func (c *StatusCache) Retain(worktrees []Worktree) {
+	keep := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		keep[wt.Path] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for path := range c.entries {
+		if !keep[path] {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// Save writes the cache to disk
+func (c *StatusCache) Save() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal status cache: %w", err)
+	}
+
+	// Write atomically so concurrent wtx processes never read a partial file
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write status cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write status cache: %w", err)
+	}
+
+	return nil
+}
+
+// key computes a worktree's cache key by reading the index and refs directly,
+// without forking git. ok is false when the worktree can't be inspected.
+func (c *StatusCache) key(wt Worktree, upstream string) (key statusKey, ok bool) {
+	gitDir := worktreeGitDir(wt.Path)
+	if gitDir == "" {
+		return key, false
+	}
+
+	// A fresh worktree may not have an index yet; that is a valid state too
+	if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		key.IndexModTime = info.ModTime().UnixNano()
+		key.IndexSize = info.Size()
+	}
+
+	key.Head = resolveRef(gitDir, c.commonDir, "HEAD")
+	if key.Head == "" {
+		return key, false
+	}
+
+	if upstream != "" {
+		key.Upstream = upstream
+		key.UpstreamOID = resolveRef("", c.commonDir, "refs/remotes/"+upstream)
+		if key.UpstreamOID == "" {
+			// Branches can track other local branches
+			key.UpstreamOID = resolveRef("", c.commonDir, "refs/heads/"+upstream)
+		}
+	}
+
+	return key, true
+}
+
+// GitDir returns the repository's common git directory
+func (m *Manager) GitDir() string {
+	return m.repo.gitDir()
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusCache(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 2)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	cache := LoadStatusCache(mgr.GitDir())
+	statuses := mgr.GetStatuses(worktrees)
+	for _, wt := range worktrees {
+		cache.Put(wt, statuses[wt.Path])
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Statuses survive a reload and are fresh while nothing changed
+	cache = LoadStatusCache(mgr.GitDir())
+	for _, wt := range worktrees {
+		if status, fresh := cache.Lookup(wt); status == nil || !status.Clean || !fresh {
+			t.Errorf("Expected fresh clean status for %s, got %+v (fresh %v)", wt.Name, status, fresh)
+		}
+	}
+
+	// Staging changes the index and committing changes HEAD, so either makes
+	// the entry stale
+	wt := *findWorktree(t, mgr, "wt-0")
+	if err := os.WriteFile(filepath.Join(wt.Path, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, wt.Path, "add", "new.txt")
+	if status, fresh := cache.Lookup(wt); status == nil || fresh {
+		t.Errorf("Expected a stale placeholder after staging, got %+v (fresh %v)", status, fresh)
+	}
+	cache.Put(wt, mustStatus(t, mgr, wt.Path))
+	runGit(t, wt.Path, "commit", "-q", "-m", "Add new.txt")
+	if _, fresh := cache.Lookup(wt); fresh {
+		t.Error("Expected a stale entry after committing")
+	}
+
+	// Edits that don't touch the index or HEAD go unnoticed until the entry
+	// is too old to be trusted
+	cache.Put(wt, mustStatus(t, mgr, wt.Path))
+	if err := os.WriteFile(filepath.Join(wt.Path, "README.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, fresh := cache.Lookup(wt); !fresh {
+		t.Error("Expected the entry to still be fresh")
+	}
+	cache.entries[wt.Path].CheckedAt = time.Now().Add(-statusCacheMaxAge - time.Second)
+	if status, fresh := cache.Lookup(wt); status == nil || fresh {
+		t.Errorf("Expected an expired placeholder, got %+v (fresh %v)", status, fresh)
+	}
+	if status := mustStatus(t, mgr, wt.Path); status.Clean || status.Unstaged != 1 {
+		t.Errorf("Expected 1 unstaged file, got %+v", status)
+	}
+
+	// Entries for removed worktrees are dropped
+	other := *findWorktree(t, mgr, "wt-1")
+	cache.Retain([]Worktree{wt})
+	if status, _ := cache.Lookup(other); status != nil {
+		t.Error("Expected wt-1 to be dropped from the cache")
+	}
+}
+
+func mustStatus(t *testing.T, mgr *Manager, path string) *Status {
+	t.Helper()
+	status, err := mgr.GetStatus(path)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	return status
+}
+
+func TestResolveRef(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	want := strings.TrimSpace(runGit(t, repoPath, "rev-parse", "HEAD"))
+
+	gitDir := filepath.Join(repoPath, ".git")
+	if got := resolveRef(gitDir, gitDir, "HEAD"); got != want {
+		t.Errorf("resolveRef(HEAD) = %q, want %q", got, want)
+	}
+
+	runGit(t, repoPath, "pack-refs", "--all")
+	if got := resolveRef(gitDir, gitDir, "HEAD"); got != want {
+		t.Errorf("resolveRef(HEAD) after pack-refs = %q, want %q", got, want)
+	}
+	if got := resolveRef(gitDir, gitDir, "refs/heads/missing"); got != "" {
+		t.Errorf("resolveRef(missing) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SubmoduleState describes a submodule's checkout relative to the commit
+// recorded in the superproject
+type SubmoduleState string
+
+const (
+	SubmoduleUpToDate      SubmoduleState = "up to date"
+	SubmoduleUninitialized SubmoduleState = "not initialized"
+	SubmoduleOutOfDate     SubmoduleState = "out of date"
+	SubmoduleConflict      SubmoduleState = "merge conflict"
+)
+
+// Submodule is one entry of git submodule status
+type Submodule struct {
+	Path   string
+	Commit string
+	State  SubmoduleState
+}
+
+// HasSubmodules reports whether a worktree declares submodules
+func HasSubmodules(worktreePath string) bool {
+	_, err := os.Stat(filepath.Join(worktreePath, ".gitmodules"))
+	return err == nil
+}
+
+// UsesLFS reports whether any tracked .gitattributes in a worktree, at any
+// depth, or the repository's info/attributes routes files through the LFS
+// filter
+func (m *Manager) UsesLFS(worktreePath string) bool {
+	paths := []string{filepath.Join(m.repo.gitDir(), "info", "attributes")}
+	// git's pathspec wildcards match across directories
+	if output, err := m.gitIn(worktreePath, "ls-files", "-z", "--", ".gitattributes", "*/.gitattributes"); err == nil {
+		for _, path := range splitNUL(output) {
+			paths = append(paths, filepath.Join(worktreePath, path))
+		}
+	}
+
+	for _, path := range paths {
+		if attributesUseLFS(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributesUseLFS reports whether a gitattributes file sets filter=lfs on
+// any pattern
+func attributesUseLFS(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, attr := range strings.Fields(line) {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsLFSInstalled checks if the git-lfs extension is available
+func IsLFSInstalled() bool {
+	_, err := exec.LookPath("git-lfs")
+	return err == nil
+}
+
+// Submodules returns the state of every submodule in a worktree, recursively
+func (m *Manager) Submodules(worktreePath string) ([]Submodule, error) {
+	output, err := m.gitIn(worktreePath, "submodule", "status", "--recursive")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodule status: %s", errOutput(err))
+	}
+	return parseSubmoduleStatus(string(output)), nil
+}
+
+// parseSubmoduleStatus parses git submodule status lines such as
+// "-3f2a... vendor/lib" or "+9b1c... firmware (v1.2-3-g9b1c)"
+func parseSubmoduleStatus(output string) []Submodule {
+	var submodules []Submodule
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+
+		var state SubmoduleState
+		switch line[0] {
+		case '-':
+			state = SubmoduleUninitialized
+		case '+':
+			state = SubmoduleOutOfDate
+		case 'U':
+			state = SubmoduleConflict
+		default:
+			state = SubmoduleUpToDate
+		}
+
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+		submodules = append(submodules, Submodule{
+			Path:   fields[1],
+			Commit: fields[0],
+			State:  state,
+		})
+	}
+	return submodules
+}
+
+// UpdateSubmodules initializes and checks out all submodules recursively. If
+// progress is set, git's progress output is streamed to it as the
+// submodules are cloned.
+func (m *Manager) UpdateSubmodules(worktreePath string, progress io.Writer) error {
+	args := []string{"submodule", "update", "--init", "--recursive"}
+	if progress != nil {
+		args = append(args, "--progress")
+	}
+	if _, err := m.run(Command{Dir: worktreePath, Args: args, NoTimeout: true, Stderr: progress}); err != nil {
+		return fmt.Errorf("failed to update submodules: %s", errOutput(err))
+	}
+	return nil
+}
+
+// PullLFS downloads and checks out the LFS objects of a worktree. If
+// progress is set, git-lfs's progress output is streamed to it.
+func (m *Manager) PullLFS(worktreePath string, progress io.Writer) error {
+	if !IsLFSInstalled() {
+		return fmt.Errorf("git-lfs is not installed")
+	}
+
+	c := Command{Dir: worktreePath, Args: []string{"lfs", "pull"}, NoTimeout: true}
+	if progress != nil {
+		// git-lfs only reports progress to a terminal unless forced
+		c.Env = []string{"GIT_LFS_FORCE_PROGRESS=1"}
+		c.Stderr = progress
+	}
+	if _, err := m.run(c); err != nil {
+		return fmt.Errorf("failed to pull LFS objects: %s", errOutput(err))
+	}
+	return nil
+}
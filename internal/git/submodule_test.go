@@ -0,0 +1,142 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	output := "-1111111111111111111111111111111111111111 vendor/lib\n" +
+		"+2222222222222222222222222222222222222222 firmware (v1.2-3-g2222222)\n" +
+		" 3333333333333333333333333333333333333333 docs (heads/main)\n" +
+		"U4444444444444444444444444444444444444444 conflicted\n"
+
+	submodules := parseSubmoduleStatus(output)
+	want := []Submodule{
+		{Path: "vendor/lib", Commit: "1111111111111111111111111111111111111111", State: SubmoduleUninitialized},
+		{Path: "firmware", Commit: "2222222222222222222222222222222222222222", State: SubmoduleOutOfDate},
+		{Path: "docs", Commit: "3333333333333333333333333333333333333333", State: SubmoduleUpToDate},
+		{Path: "conflicted", Commit: "4444444444444444444444444444444444444444", State: SubmoduleConflict},
+	}
+	if len(submodules) != len(want) {
+		t.Fatalf("got %d submodules, want %d", len(submodules), len(want))
+	}
+	for i := range want {
+		if submodules[i] != want[i] {
+			t.Errorf("submodule %d = %+v, want %+v", i, submodules[i], want[i])
+		}
+	}
+}
+
+func TestUsesLFS(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	if mgr.UsesLFS(repoPath) {
+		t.Error("expected no LFS without .gitattributes")
+	}
+
+	// Only a nested .gitattributes routes files through LFS
+	if err := os.WriteFile(filepath.Join(repoPath, ".gitattributes"), []byte("*.txt text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(repoPath, "assets", "models")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	attrs := "# binaries\n*.bin filter=lfs diff=lfs merge=lfs -text\n"
+	if err := os.WriteFile(filepath.Join(nested, ".gitattributes"), []byte(attrs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if mgr.UsesLFS(repoPath) {
+		t.Error("expected untracked .gitattributes to be ignored")
+	}
+
+	runGit(t, repoPath, "add", ".gitattributes", "assets")
+	if !mgr.UsesLFS(repoPath) {
+		t.Error("expected LFS in a nested .gitattributes to be detected")
+	}
+}
+
+func TestUpdateSubmodules(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	// Local submodule URLs need the file protocol, which git disables by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	libPath := filepath.Join(filepath.Dir(repoPath), "lib")
+	runGit(t, filepath.Dir(repoPath), "init", "-q", "lib")
+	runGit(t, libPath, "commit", "-q", "--allow-empty", "-m", "lib")
+	runGit(t, repoPath, "submodule", "add", "-q", libPath, "vendor/lib")
+	runGit(t, repoPath, "commit", "-q", "-m", "Add submodule")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	path, err := mgr.Add("sub", "sub", "")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if !HasSubmodules(path) {
+		t.Fatal("expected .gitmodules to be detected")
+	}
+	submodules, err := mgr.Submodules(path)
+	if err != nil {
+		t.Fatalf("Submodules failed: %v", err)
+	}
+	if len(submodules) != 1 || submodules[0].State != SubmoduleUninitialized {
+		t.Fatalf("submodules = %+v, want one uninitialized", submodules)
+	}
+
+	if err := mgr.UpdateSubmodules(path, nil); err != nil {
+		t.Fatalf("UpdateSubmodules failed: %v", err)
+	}
+	submodules, err = mgr.Submodules(path)
+	if err != nil {
+		t.Fatalf("Submodules failed: %v", err)
+	}
+	if len(submodules) != 1 || submodules[0].State != SubmoduleUpToDate {
+		t.Errorf("submodules = %+v, want one up to date", submodules)
+	}
+
+	// Adding reports the submodules, and initializes them when asked
+	result, err := mgr.AddWithOptions(AddOptions{Name: "plain", Branch: "plain"})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if !result.HasSubmodules || result.SubmodulesUpdated || result.UsesLFS {
+		t.Errorf("AddWithOptions = %+v, want submodules left alone", result)
+	}
+
+	result, err = mgr.AddWithOptions(AddOptions{Name: "init", Branch: "init", Submodules: true})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+	if !result.SubmodulesUpdated || result.SubmoduleErr != nil {
+		t.Errorf("AddWithOptions = %+v, want submodules initialized", result)
+	}
+	if !fileExists(filepath.Join(result.Path, "vendor", "lib", ".git")) {
+		t.Error("expected the submodule to be checked out")
+	}
+
+	// Progress names the step and streams git's output
+	var progress strings.Builder
+	result, err = mgr.AddWithOptions(AddOptions{Name: "progress", Branch: "progress", Submodules: true, Progress: &progress})
+	if err != nil || !result.SubmodulesUpdated {
+		t.Fatalf("AddWithOptions = %+v, %v, want submodules initialized", result, err)
+	}
+	if out := progress.String(); !strings.HasPrefix(out, "Initializing submodules...\n") || !strings.Contains(out, "vendor/lib") {
+		t.Errorf("progress = %q, want the step and git's output", out)
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"fmt"
+)
+
+// SyncOutcome is what happened to a worktree during a sync
+type SyncOutcome string
+
+const (
+	SyncUpdated  SyncOutcome = "updated"
+	SyncUpToDate SyncOutcome = "up-to-date"
+	SyncSkipped  SyncOutcome = "skipped"
+	SyncConflict SyncOutcome = "conflict"
+	SyncFailed   SyncOutcome = "failed"
+)
+
+// SyncResult is the outcome of syncing a single worktree
+type SyncResult struct {
+	Worktree Worktree
+	Outcome  SyncOutcome
+	// Detail explains the outcome, e.g. "dirty" or "fast-forwarded 3 commits"
+	Detail string
+}
+
+// Fetch updates remote-tracking branches for all remotes in a single call,
+// pruning branches that were deleted upstream
+func (m *Manager) Fetch() error {
+	// Network speed is unpredictable, so fetch is exempt from the timeout
+	if _, err := m.gitWrite(m.repo.Path, "fetch", "--all", "--prune"); err != nil {
+		return fmt.Errorf("failed to fetch: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// Sync brings each worktree up to date with its upstream. Clean worktrees
+// that are strictly behind are fast-forwarded; diverged ones are rebased only
+// if rebase is set. Dirty worktrees are never touched. Call Fetch first.
+func (m *Manager) Sync(worktrees []Worktree, rebase bool) []SyncResult {
+	results := make([]SyncResult, 0, len(worktrees))
+	for _, wt := range worktrees {
+		results = append(results, m.syncWorktree(wt, rebase))
+	}
+	return results
+}
+
+// syncWorktree syncs a single worktree
+func (m *Manager) syncWorktree(wt Worktree, rebase bool) SyncResult {
+	result := SyncResult{Worktree: wt, Outcome: SyncSkipped}
+
+	switch {
+	case wt.Detached || wt.Branch == "":
+		result.Detail = "no branch"
+		return result
+	case m.Diagnose(wt) != ProblemNone:
+		result.Detail = "broken worktree"
+		return result
+	}
+
+	status, err := m.GetStatus(wt.Path)
+	if err != nil {
+		result.Outcome = SyncFailed
+		result.Detail = err.Error()
+		return result
+	}
+
+	switch {
+	case status.Upstream == "":
+		result.Detail = "no upstream"
+		return result
+	case status.UpstreamGone:
+		result.Detail = "upstream gone"
+		return result
+	case status.Operation != OperationNone:
+		result.Detail = fmt.Sprintf("%s in progress", status.Operation)
+		return result
+	case !status.Clean:
+		result.Detail = "dirty"
+		return result
+	case status.Behind == 0:
+		result.Outcome = SyncUpToDate
+		if status.Ahead > 0 {
+			result.Detail = fmt.Sprintf("%d to push", status.Ahead)
+		}
+		return result
+	}
+
+	// Strictly behind: fast-forward
+	if status.Ahead == 0 {
+		if _, err := m.gitWrite(wt.Path, "merge", "--ff-only", "@{upstream}"); err != nil {
+			result.Outcome = SyncFailed
+			result.Detail = errOutput(err)
+			return result
+		}
+		result.Outcome = SyncUpdated
+		result.Detail = fmt.Sprintf("fast-forwarded %d commit(s)", status.Behind)
+		return result
+	}
+
+	// Diverged
+	if !rebase {
+		result.Detail = fmt.Sprintf("diverged (↑%d ↓%d)", status.Ahead, status.Behind)
+		return result
+	}
+
+	if _, err := m.gitWrite(wt.Path, "rebase", "@{upstream}"); err != nil {
+		// Leave the worktree exactly as it was
+		_, _ = m.gitWrite(wt.Path, "rebase", "--abort")
+		result.Outcome = SyncConflict
+		result.Detail = "rebase aborted"
+		return result
+	}
+
+	result.Outcome = SyncUpdated
+	result.Detail = fmt.Sprintf("rebased %d commit(s) onto %s", status.Ahead, status.Upstream)
+	return result
+}
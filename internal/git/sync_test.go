@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	root := filepath.Dir(repoPath)
+	remotePath := filepath.Join(root, "remote.git")
+	otherPath := filepath.Join(root, "other")
+
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", file)
+		runGit(t, dir, "commit", "-m", file)
+	}
+
+	// Publish four branches to a local bare remote
+	runGit(t, root, "init", "--bare", "remote.git")
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+	branches := []string{"behind", "dirty", "diverged", "conflict"}
+	for _, b := range branches {
+		runGit(t, repoPath, "branch", b)
+		runGit(t, repoPath, "push", "-u", "origin", b)
+	}
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	for _, b := range branches {
+		if _, err := mgr.Add(b, b, ""); err != nil {
+			t.Fatalf("Add(%s) failed: %v", b, err)
+		}
+	}
+	wtPath := func(name string) string { return findWorktree(t, mgr, name).Path }
+
+	// Someone else pushes to every branch
+	runGit(t, root, "clone", "-q", remotePath, "other")
+	for _, b := range branches {
+		runGit(t, otherPath, "checkout", "-q", b)
+		commit(otherPath, "upstream-"+b+".txt")
+		runGit(t, otherPath, "push", "-q", "origin", b)
+	}
+
+	// Local changes
+	if err := os.WriteFile(filepath.Join(wtPath("dirty"), "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit(wtPath("diverged"), "local.txt")
+	commit(wtPath("conflict"), "upstream-conflict.txt")
+
+	if err := mgr.Fetch(); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	want := map[string]SyncOutcome{
+		"repo":     SyncSkipped, // main has no upstream
+		"behind":   SyncUpdated,
+		"dirty":    SyncSkipped,
+		"diverged": SyncSkipped,
+		"conflict": SyncSkipped,
+	}
+	for _, r := range mgr.Sync(worktrees, false) {
+		if r.Outcome != want[r.Worktree.Name] {
+			t.Errorf("Sync(%s) = %s (%s), want %s", r.Worktree.Name, r.Outcome, r.Detail, want[r.Worktree.Name])
+		}
+	}
+
+	want["behind"] = SyncUpToDate
+	want["diverged"] = SyncUpdated
+	want["conflict"] = SyncConflict
+	for _, r := range mgr.Sync(worktrees, true) {
+		if r.Outcome != want[r.Worktree.Name] {
+			t.Errorf("Sync(%s, rebase) = %s (%s), want %s", r.Worktree.Name, r.Outcome, r.Detail, want[r.Worktree.Name])
+		}
+	}
+
+	// The aborted rebase leaves the worktree as it was
+	status, err := mgr.GetStatus(wtPath("conflict"))
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if status.Operation != OperationNone || status.Ahead != 1 || status.Behind != 1 {
+		t.Errorf("conflict worktree status = %+v, want clean diverged state", status)
+	}
+}
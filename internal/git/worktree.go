@@ -1,9 +1,12 @@
 package git
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Worktree represents a git worktree
@@ -13,24 +16,113 @@ type Worktree struct {
 	Branch string
 	Head   string
 	IsMain bool
+
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
 }
 
 // Manager handles git worktree operations
 type Manager struct {
-	repo *Repository
+	repo            *Repository
+	worktreeDir     string
+	preferredRemote string
+	statusWorkers   int
+	runner          Runner
+	ctx             context.Context
 }
 
 // NewManager creates a new worktree manager
 func NewManager(repo *Repository) *Manager {
-	return &Manager{repo: repo}
+	return &Manager{
+		repo:          repo,
+		worktreeDir:   DefaultWorktreeDir,
+		statusWorkers: DefaultStatusWorkers,
+		runner:        NewExecRunner(),
+		ctx:           context.Background(),
+	}
+}
+
+// SetRunner replaces the runner used for git commands
+func (m *Manager) SetRunner(runner Runner) {
+	m.runner = runner
+}
+
+// WithContext returns a copy of the manager whose git commands are cancelled
+// when ctx is done
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	copy := *m
+	copy.ctx = ctx
+	return &copy
+}
+
+// run executes a git command through the manager's runner
+func (m *Manager) run(c Command) ([]byte, error) {
+	return m.runner.Run(m.ctx, c)
+}
+
+// git runs a git command in the main worktree
+func (m *Manager) git(args ...string) ([]byte, error) {
+	return m.run(Command{Dir: m.repo.Path, Args: args})
+}
+
+// gitIn runs a git command in the given directory
+func (m *Manager) gitIn(dir string, args ...string) ([]byte, error) {
+	return m.run(Command{Dir: dir, Args: args})
+}
+
+// gitWrite runs a git command that modifies a working tree. It is exempt from
+// the runner's timeout so it is never interrupted halfway.
+func (m *Manager) gitWrite(dir string, args ...string) ([]byte, error) {
+	return m.run(Command{Dir: dir, Args: args, NoTimeout: true})
 }
 
-// List returns all worktrees in the repository
+// SetWorktreeDir sets the layout used for new worktrees. It accepts a preset
+// name (sibling, nested, central) or a template with {repo}, {name}, {branch}
+// and ~ placeholders. Relative templates are resolved against the main worktree.
+func (m *Manager) SetWorktreeDir(layout string) {
+	m.worktreeDir = layout
+}
+
+// SetPreferredRemote sets the remote used when a branch exists on several
+// remotes and none was requested explicitly
+func (m *Manager) SetPreferredRemote(remote string) {
+	m.preferredRemote = remote
+}
+
+// WorktreePath returns the path a new worktree with the given name and branch
+// would be created at
+func (m *Manager) WorktreePath(name, branch string) (string, error) {
+	vars := LayoutVars{
+		Repo:   m.repo.Name(),
+		Name:   name,
+		Branch: branch,
+	}
+	return ExpandLayout(m.worktreeDir, vars, m.repo.Path)
+}
+
+// List returns all worktrees in the repository. It reads the repository's
+// administrative files directly when it can and falls back to git otherwise.
 func (m *Manager) List() ([]Worktree, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = m.repo.Path
+	if m.canListNatively() {
+		if worktrees, err := m.listNative(); err == nil {
+			return worktrees, nil
+		}
+	}
+	return m.listExec()
+}
 
-	output, err := cmd.Output()
+// listExec lists worktrees with git worktree list
+func (m *Manager) listExec() ([]Worktree, error) {
+	output, err := m.git("worktree", "list", "--porcelain", "-z")
+	if err == nil {
+		return parseWorktreeListZ(string(output))
+	}
+
+	// git < 2.36 doesn't support -z
+	output, err = m.git("worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -38,36 +130,208 @@ func (m *Manager) List() ([]Worktree, error) {
 	return parseWorktreeList(string(output))
 }
 
+// AddOptions configures how a worktree is created
+type AddOptions struct {
+	Name   string
+	Branch string
+	// Base is the start point for a new branch; empty uses DefaultBranch
+	Base string
+	// Remote restricts the search for an existing remote branch; empty
+	// searches all remotes
+	Remote string
+	// SparsePaths makes the worktree a cone-mode sparse checkout of these
+	// directories; nil checks out everything
+	SparsePaths []string
+	// At creates a detached worktree at a tag, commit or other ref instead
+	// of checking out a branch; Branch, Base and Remote are ignored
+	At string
+	// SkipIncludes doesn't bring in the files listed in IncludeFile
+	SkipIncludes bool
+	// Submodules initializes submodules recursively if the worktree has any
+	Submodules bool
+	// LFS downloads Git LFS files if the worktree uses LFS
+	LFS bool
+	// Progress, if set, receives a line naming each of those steps as it
+	// starts, followed by git's progress output for it
+	Progress io.Writer
+}
+
+// step announces a step of preparing the worktree on Progress
+func (opts AddOptions) step(text string) {
+	if opts.Progress != nil {
+		fmt.Fprintln(opts.Progress, text)
+	}
+}
+
+// AddResult describes a created worktree
+type AddResult struct {
+	Path string
+	// Upstream is set when a local branch was created to track a remote branch
+	Upstream string
+	// Commit is the commit a detached worktree was created at
+	Commit string
+	// Included lists the paths brought in from the main worktree by
+	// IncludeFile
+	Included []IncludedPath
+	// IncludeErr is set if bringing in those paths failed; the worktree is
+	// kept
+	IncludeErr error
+
+	// HasSubmodules is set when the worktree declares submodules, and
+	// SubmodulesUpdated when they were initialized as requested
+	HasSubmodules     bool
+	SubmodulesUpdated bool
+	// SubmoduleErr is set if initializing them failed; the worktree is kept
+	SubmoduleErr error
+	// UsesLFS is set when the worktree has LFS files, and LFSPulled when
+	// they were downloaded as requested
+	UsesLFS   bool
+	LFSPulled bool
+	// LFSErr is set if downloading them failed; the worktree is kept
+	LFSErr error
+}
+
+// AmbiguousBranchError is returned when a branch exists on several remotes and
+// none of them was requested or preferred
+type AmbiguousBranchError struct {
+	Branch  string
+	Remotes []string
+}
+
+func (e *AmbiguousBranchError) Error() string {
+	return fmt.Sprintf("branch '%s' exists on multiple remotes (%s); choose one with --remote",
+		e.Branch, strings.Join(e.Remotes, ", "))
+}
+
 // Add creates a new worktree
 func (m *Manager) Add(name, branch string, baseBranch string) (string, error) {
-	// Determine worktree path
-	worktreePath := filepath.Join(m.repo.Path, "..", "worktrees", name)
-
-	// Check if branch exists
-	branchExists, err := m.branchExists(branch)
+	result, err := m.AddWithOptions(AddOptions{Name: name, Branch: branch, Base: baseBranch})
 	if err != nil {
 		return "", err
 	}
+	return result.Path, nil
+}
+
+// AddWithOptions creates a new worktree. An existing local branch is checked
+// out as is; a branch that only exists on a remote gets a local branch with
+// its upstream set; otherwise a new branch is created from the base.
+func (m *Manager) AddWithOptions(opts AddOptions) (*AddResult, error) {
+	// Detached worktrees have no branch to place in the layout
+	layoutBranch := opts.Branch
+	if opts.At != "" {
+		layoutBranch = opts.Name
+	}
+
+	// Determine worktree path
+	worktreePath, err := m.WorktreePath(opts.Name, layoutBranch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worktree_dir: %w", err)
+	}
 
-	var cmd *exec.Cmd
-	if branchExists {
+	result := &AddResult{Path: worktreePath}
+
+	var args []string
+	// createsBranch is set when git worktree add creates opts.Branch
+	createsBranch := false
+	if opts.At != "" {
+		commit, err := m.resolveCommit(opts.At)
+		if err != nil {
+			return nil, err
+		}
+		result.Commit = commit
+		args = []string{"worktree", "add", "--detach", worktreePath, commit}
+	} else if m.localBranchExists(opts.Branch) {
 		// Checkout existing branch
-		cmd = exec.Command("git", "worktree", "add", worktreePath, branch)
+		args = []string{"worktree", "add", worktreePath, opts.Branch}
 	} else {
-		// Create new branch from base
-		if baseBranch == "" {
-			baseBranch = "main"
+		remote, err := m.findRemoteBranch(opts.Branch, opts.Remote)
+		if err != nil {
+			return nil, err
+		}
+
+		if remote != "" {
+			// Track the remote branch explicitly rather than relying on DWIM
+			result.Upstream = remote + "/" + opts.Branch
+			args = []string{"worktree", "add", "--track", "-b", opts.Branch, worktreePath, result.Upstream}
+			createsBranch = true
+		} else {
+			// Create new branch from base
+			base := opts.Base
+			if base == "" {
+				base = m.DefaultBranch()
+			}
+			args = []string{"worktree", "add", "-b", opts.Branch, worktreePath, base}
+			createsBranch = true
+		}
+	}
+
+	// Keep worktrees nested inside the main worktree out of its status
+	if isNestedPath(m.repo.Path, worktreePath) {
+		if err := m.excludeNested(worktreePath); err != nil {
+			return nil, err
 		}
-		cmd = exec.Command("git", "worktree", "add", "-b", branch, worktreePath, baseBranch)
 	}
 
-	cmd.Dir = m.repo.Path
+	// Sparse worktrees are checked out only after the cone is set, so the
+	// full tree never touches the disk
+	if opts.SparsePaths != nil {
+		args = append([]string{"worktree", "add", "--no-checkout"}, args[2:]...)
+	}
+
+	if _, err := m.gitWrite(m.repo.Path, args...); err != nil {
+		return nil, fmt.Errorf("failed to create worktree: %s", errOutput(err))
+	}
+
+	if opts.SparsePaths != nil {
+		if err := m.checkoutSparse(worktreePath, opts.SparsePaths); err != nil {
+			// Don't leave an empty worktree or a branch nobody asked for
+			// behind, just as a failed git worktree add doesn't
+			_ = m.Remove(worktreePath, true)
+			if createsBranch {
+				_, _ = m.git("branch", "-D", opts.Branch)
+			}
+			return nil, err
+		}
+	}
+
+	if !opts.SkipIncludes {
+		result.Included, result.IncludeErr = m.ApplyIncludes(worktreePath, false)
+	}
+
+	result.HasSubmodules = HasSubmodules(worktreePath)
+	if result.HasSubmodules && opts.Submodules {
+		opts.step("Initializing submodules...")
+		result.SubmoduleErr = m.UpdateSubmodules(worktreePath, opts.Progress)
+		result.SubmodulesUpdated = result.SubmoduleErr == nil
+	}
+	result.UsesLFS = m.UsesLFS(worktreePath)
+	if result.UsesLFS && opts.LFS {
+		opts.step("Downloading LFS files...")
+		result.LFSErr = m.PullLFS(worktreePath, opts.Progress)
+		result.LFSPulled = result.LFSErr == nil
+	}
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("failed to create worktree: %s", string(output))
+	return result, nil
+}
+
+// resolveCommit returns the commit a tag, SHA or ref points at
+func (m *Manager) resolveCommit(rev string) (string, error) {
+	output, err := m.git("rev-parse", "--verify", "--quiet", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a commit, tag or branch", rev)
 	}
+	return strings.TrimSpace(string(output)), nil
+}
 
-	return worktreePath, nil
+// checkoutSparse populates a worktree created with --no-checkout
+func (m *Manager) checkoutSparse(worktreePath string, paths []string) error {
+	if err := m.SetSparsePaths(worktreePath, paths); err != nil {
+		return err
+	}
+	if _, err := m.gitWrite(worktreePath, "checkout"); err != nil {
+		return fmt.Errorf("failed to check out sparse worktree: %s", errOutput(err))
+	}
+	return nil
 }
 
 // Remove deletes a worktree
@@ -78,42 +342,109 @@ func (m *Manager) Remove(name string, force bool) error {
 	}
 	args = append(args, name)
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = m.repo.Path
+	if _, err := m.gitWrite(m.repo.Path, args...); err != nil {
+		return fmt.Errorf("failed to remove worktree: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// Lock marks a worktree as locked so it isn't pruned, moved or removed
+func (m *Manager) Lock(name, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, name)
+
+	if _, err := m.git(args...); err != nil {
+		return fmt.Errorf("failed to lock worktree: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// Unlock removes the lock from a worktree
+func (m *Manager) Unlock(name string) error {
+	if _, err := m.git("worktree", "unlock", name); err != nil {
+		return fmt.Errorf("failed to unlock worktree: %s", errOutput(err))
+	}
+
+	return nil
+}
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to remove worktree: %s", string(output))
+// UnlockAndRemove removes a locked worktree. The lock is restored with its
+// original reason if removal fails, e.g. because of uncommitted changes.
+func (m *Manager) UnlockAndRemove(wt Worktree, force bool) error {
+	if !wt.Locked {
+		return m.Remove(wt.Name, force)
+	}
+
+	if err := m.Unlock(wt.Name); err != nil {
+		return err
+	}
+
+	if err := m.Remove(wt.Name, force); err != nil {
+		if lockErr := m.Lock(wt.Name, wt.LockReason); lockErr != nil {
+			return fmt.Errorf("%w (and failed to restore lock: %v)", err, lockErr)
+		}
+		return err
 	}
 
 	return nil
 }
 
-// branchExists checks if a branch exists locally or remotely
-func (m *Manager) branchExists(branch string) (bool, error) {
-	// Check local
-	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
-	cmd.Dir = m.repo.Path
-	if err := cmd.Run(); err == nil {
-		return true, nil
+// Move relocates a worktree to newPath, which must not exist yet. Locked
+// worktrees can't be moved.
+func (m *Manager) Move(name, newPath string) error {
+	// git moves the worktree inside an existing directory rather than to it
+	if _, err := os.Lstat(newPath); err == nil {
+		return fmt.Errorf("destination '%s' already exists", newPath)
+	}
+
+	// Keep worktrees moved inside the main worktree out of its status
+	if isNestedPath(m.repo.Path, newPath) {
+		if err := m.excludeNested(newPath); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
-	// Check remote
-	cmd = exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch)
-	cmd.Dir = m.repo.Path
-	if err := cmd.Run(); err == nil {
-		return true, nil
+	if _, err := m.gitWrite(m.repo.Path, "worktree", "move", name, newPath); err != nil {
+		return fmt.Errorf("failed to move worktree: %s", errOutput(err))
+	}
+
+	return nil
+}
+
+// RenameBranch renames a local branch. Worktrees that have it checked out
+// follow the rename.
+func (m *Manager) RenameBranch(oldName, newName string) error {
+	if _, err := m.git("branch", "-m", oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename branch: %s", errOutput(err))
 	}
 
-	return false, nil
+	return nil
+}
+
+// excludeNested adds the top-level directory holding a nested worktree to
+// info/exclude
+func (m *Manager) excludeNested(worktreePath string) error {
+	rel, err := filepath.Rel(m.repo.Path, worktreePath)
+	if err != nil {
+		return err
+	}
+	top := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+	return ensureExcluded(m.repo.gitDir(), "/"+top+"/")
 }
 
 // Prune removes worktree entries that no longer exist
 func (m *Manager) Prune() error {
-	cmd := exec.Command("git", "worktree", "prune")
-	cmd.Dir = m.repo.Path
-
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to prune worktrees: %s", string(output))
+	if _, err := m.git("worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %s", errOutput(err))
 	}
 
 	return nil
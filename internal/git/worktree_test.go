@@ -2,6 +2,8 @@ package git
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -80,3 +82,273 @@ func BenchmarkParseWorktreeList(b *testing.B) {
 		}
 	}
 }
+
+func TestParseWorktreeListZ(t *testing.T) {
+	output := "worktree /path/to/main\x00HEAD abc1234\x00branch refs/heads/main\x00\x00" +
+		"worktree /path/to/detached\x00HEAD def5678\x00detached\x00\x00" +
+		"worktree /path/to/usb\x00HEAD 1111111\x00branch refs/heads/usb\x00locked on removable\ndrive\x00prunable gitdir file points to non-existent location\x00\x00" +
+		"worktree /path/with\nnewline\x00HEAD 2222222\x00branch refs/heads/nl\x00locked\x00\x00"
+
+	worktrees, err := parseWorktreeListZ(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(worktrees) != 4 {
+		t.Fatalf("expected 4 worktrees, got %d", len(worktrees))
+	}
+
+	if !worktrees[0].IsMain || worktrees[0].Branch != "main" {
+		t.Errorf("worktree[0] = %+v, want main worktree on main", worktrees[0])
+	}
+
+	if !worktrees[1].Detached || worktrees[1].Branch != "" {
+		t.Errorf("worktree[1] = %+v, want detached", worktrees[1])
+	}
+
+	usb := worktrees[2]
+	if !usb.Locked || usb.LockReason != "on removable\ndrive" {
+		t.Errorf("worktree[2] lock = %v %q", usb.Locked, usb.LockReason)
+	}
+	if !usb.Prunable || usb.PrunableReason != "gitdir file points to non-existent location" {
+		t.Errorf("worktree[2] prunable = %v %q", usb.Prunable, usb.PrunableReason)
+	}
+
+	nl := worktrees[3]
+	if nl.Path != "/path/with\nnewline" || nl.Name != "with\nnewline" {
+		t.Errorf("worktree[3] path = %q, name = %q", nl.Path, nl.Name)
+	}
+	if !nl.Locked || nl.LockReason != "" {
+		t.Errorf("worktree[3] lock = %v %q, want locked without reason", nl.Locked, nl.LockReason)
+	}
+}
+
+func TestParseWorktreeListStates(t *testing.T) {
+	output := `worktree /path/to/main
+HEAD abc1234
+branch refs/heads/main
+
+worktree /path/to/locked
+HEAD def5678
+detached
+locked release branch
+`
+	worktrees, err := parseWorktreeList(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+	if wt := worktrees[1]; !wt.Detached || !wt.Locked || wt.LockReason != "release branch" {
+		t.Errorf("worktree[1] = %+v, want detached and locked", wt)
+	}
+}
+
+func TestLockUnlock(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 1)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	if err := mgr.Lock("wt-0", "release branch"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	wt := findWorktree(t, mgr, "wt-0")
+	if !wt.Locked || wt.LockReason != "release branch" {
+		t.Fatalf("worktree = %+v, want locked with reason", wt)
+	}
+
+	// Plain removal must fail while locked
+	if err := mgr.Remove("wt-0", true); err == nil {
+		t.Fatal("expected Remove to fail on locked worktree")
+	}
+
+	if err := mgr.Unlock("wt-0"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if wt = findWorktree(t, mgr, "wt-0"); wt.Locked {
+		t.Error("expected worktree to be unlocked")
+	}
+}
+
+func TestUnlockAndRemoveRestoresLock(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 1)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	if err := mgr.Lock("wt-0", "keep me"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	wt := findWorktree(t, mgr, "wt-0")
+
+	// A dirty worktree can't be removed without force, so the lock should come back
+	if err := os.WriteFile(filepath.Join(wt.Path, "dirty.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.UnlockAndRemove(*wt, false); err == nil {
+		t.Fatal("expected UnlockAndRemove to fail on dirty worktree")
+	}
+	if wt := findWorktree(t, mgr, "wt-0"); !wt.Locked || wt.LockReason != "keep me" {
+		t.Errorf("worktree = %+v, want lock restored", wt)
+	}
+
+	if err := mgr.UnlockAndRemove(*wt, true); err != nil {
+		t.Fatalf("UnlockAndRemove failed: %v", err)
+	}
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(worktrees) != 1 {
+		t.Errorf("expected only main worktree after removal, got %d", len(worktrees))
+	}
+}
+
+// findWorktree returns the named worktree or fails the test
+func findWorktree(t *testing.T, mgr *Manager, name string) *Worktree {
+	t.Helper()
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for i := range worktrees {
+		if worktrees[i].Name == name {
+			return &worktrees[i]
+		}
+	}
+	t.Fatalf("worktree %s not found", name)
+	return nil
+}
+
+func TestMoveAndRenameBranch(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 1)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	wt := findWorktree(t, mgr, "wt-0")
+
+	// An existing directory is refused rather than moved into
+	occupied := filepath.Join(filepath.Dir(wt.Path), "occupied")
+	if err := os.Mkdir(occupied, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Move("wt-0", occupied); err == nil {
+		t.Error("expected Move onto an existing directory to fail")
+	}
+	if fileExists(filepath.Join(occupied, "wt-0")) || findWorktree(t, mgr, "wt-0").Path != wt.Path {
+		t.Error("worktree moved despite the existing destination")
+	}
+
+	newPath := filepath.Join(filepath.Dir(wt.Path), "renamed")
+	if err := mgr.Move("wt-0", newPath); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if err := mgr.RenameBranch("branch-0", "feature/renamed"); err != nil {
+		t.Fatalf("RenameBranch failed: %v", err)
+	}
+
+	moved := findWorktree(t, mgr, "renamed")
+	if moved.Path != newPath {
+		t.Errorf("Path = %q, want %q", moved.Path, newPath)
+	}
+	if moved.Branch != "feature/renamed" {
+		t.Errorf("Branch = %q, want feature/renamed", moved.Branch)
+	}
+
+	// Locked worktrees stay put
+	if err := mgr.Lock("renamed", ""); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if err := mgr.Move("renamed", wt.Path); err == nil {
+		t.Error("expected Move to fail on locked worktree")
+	}
+}
+
+func TestAddDetached(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	runGit(t, repoPath, "tag", "v1.0")
+
+	mgr := NewManager(&Repository{Path: repoPath})
+	result, err := mgr.AddWithOptions(AddOptions{Name: "release", At: "v1.0"})
+	if err != nil {
+		t.Fatalf("AddWithOptions failed: %v", err)
+	}
+
+	wt := findWorktree(t, mgr, "release")
+	if !wt.Detached || wt.Branch != "" {
+		t.Errorf("expected a detached worktree, got %+v", wt)
+	}
+	if wt.Head != result.Commit {
+		t.Errorf("Head = %q, want %q", wt.Head, result.Commit)
+	}
+	if count, err := mgr.UnreferencedCount(wt.Head); err != nil || count != 0 {
+		t.Errorf("UnreferencedCount = %d, %v; want 0", count, err)
+	}
+
+	if _, err := mgr.AddWithOptions(AddOptions{Name: "missing", At: "v9.9"}); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
+
+func TestListNativeMatchesGit(t *testing.T) {
+	repoPath, cleanup, err := setupTestRepo(t, 4)
+	if err != nil {
+		t.Fatalf("Failed to setup test repo: %v", err)
+	}
+	defer cleanup()
+
+	mgr := NewManager(&Repository{Path: repoPath})
+
+	// Cover every state the porcelain output can report
+	if err := mgr.Lock("wt-0", "on a usb stick"); err != nil {
+		t.Fatal(err)
+	}
+	wt1 := findWorktree(t, mgr, "wt-1")
+	if output, err := mgr.gitIn(wt1.Path, "checkout", "--detach"); err != nil {
+		t.Fatalf("checkout --detach failed: %v\n%s", err, output)
+	}
+	wt2 := findWorktree(t, mgr, "wt-2")
+	if err := os.RemoveAll(wt2.Path); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := mgr.git("pack-refs", "--all"); err != nil {
+		t.Fatalf("pack-refs failed: %v\n%s", err, output)
+	}
+
+	if !mgr.canListNatively() {
+		t.Fatal("expected a standard repository to be listed natively")
+	}
+	native, err := mgr.listNative()
+	if err != nil {
+		t.Fatalf("listNative failed: %v", err)
+	}
+	viaGit, err := mgr.listExec()
+	if err != nil {
+		t.Fatalf("listExec failed: %v", err)
+	}
+
+	if len(native) != len(viaGit) {
+		t.Fatalf("native listed %d worktrees, git %d", len(native), len(viaGit))
+	}
+	for i := range viaGit {
+		if native[i] != viaGit[i] {
+			t.Errorf("worktree %d:\n native %+v\n git    %+v", i, native[i], viaGit[i])
+		}
+	}
+}
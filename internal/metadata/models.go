@@ -1,6 +1,9 @@
 package metadata
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // WorktreeMetadata stores information about a worktree
 type WorktreeMetadata struct {
@@ -12,6 +15,16 @@ type WorktreeMetadata struct {
 	OpenCount  int       `json:"open_count"`
 	DevCommand string    `json:"dev_command,omitempty"`
 	Ports      []int     `json:"ports,omitempty"`
+	// SparseProfile is the sparse-checkout profile the worktree was created
+	// with
+	SparseProfile string `json:"sparse_profile,omitempty"`
+	// DetachedAt is the tag, commit or ref a detached worktree was created
+	// at with wtx add --at
+	DetachedAt string `json:"detached_at,omitempty"`
+	// PRNumber and PRBase identify the pull request a wtx review worktree
+	// checks out and the branch it targets
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRBase   string `json:"pr_base,omitempty"`
 }
 
 // Store holds all worktree metadata for a repository
@@ -19,6 +32,9 @@ type Store struct {
 	RepoPath  string                       `json:"repo_path"`
 	Worktrees map[string]*WorktreeMetadata `json:"worktrees"`
 	UpdatedAt time.Time                    `json:"updated_at"`
+
+	// gitDir is the common git directory the store is saved in
+	gitDir string
 }
 
 // NewStore creates a new metadata store
@@ -42,6 +58,65 @@ func (s *Store) Remove(name string) {
 	s.UpdatedAt = time.Now()
 }
 
+// Rename re-keys a worktree's metadata under a new name and path, keeping its
+// history, ports and dev command. It reports whether the entry was renamed;
+// it isn't if there is none, or if newName already belongs to another entry,
+// which is never overwritten.
+func (s *Store) Rename(oldName, newName, newPath string) bool {
+	wt, exists := s.Worktrees[oldName]
+	if !exists {
+		return false
+	}
+	if _, taken := s.Worktrees[newName]; taken && newName != oldName {
+		return false
+	}
+
+	delete(s.Worktrees, oldName)
+	wt.Name = newName
+	wt.Path = newPath
+	s.Worktrees[newName] = wt
+	s.UpdatedAt = time.Now()
+	return true
+}
+
+// Reconcile brings the store in line with the worktrees that actually exist,
+// given as a map of name to path. Entries whose worktree was renamed by hand
+// are re-keyed by path and moved ones get their path updated; entries with no
+// matching worktree are dropped. It returns the names that were removed and
+// the (old) names that were updated.
+func (s *Store) Reconcile(current map[string]string) (removed, updated []string) {
+	nameByPath := make(map[string]string, len(current))
+	for name, path := range current {
+		nameByPath[path] = name
+	}
+
+	for name, wt := range s.Worktrees {
+		if path, ok := current[name]; ok {
+			if wt.Path != path {
+				wt.Path = path
+				s.UpdatedAt = time.Now()
+				updated = append(updated, name)
+			}
+			continue
+		}
+
+		if newName, ok := nameByPath[wt.Path]; ok {
+			if _, taken := s.Worktrees[newName]; !taken {
+				s.Rename(name, newName, wt.Path)
+				updated = append(updated, name)
+				continue
+			}
+		}
+
+		s.Remove(name)
+		removed = append(removed, name)
+	}
+
+	sort.Strings(removed)
+	sort.Strings(updated)
+	return removed, updated
+}
+
 // Get retrieves metadata for a worktree
 func (s *Store) Get(name string) (*WorktreeMetadata, bool) {
 	wt, exists := s.Worktrees[name]
@@ -10,11 +10,20 @@ import (
 
 // Load reads metadata from disk, creating a new store if the file doesn't exist
 func Load(repoPath string) (*Store, error) {
-	path := metadataPath(repoPath)
+	return LoadFromGitDir(repoPath, filepath.Join(repoPath, ".git"))
+}
+
+// LoadFromGitDir reads metadata stored in the given git directory. Passing the
+// common git directory lets every worktree of a repository share one store,
+// including bare repositories that have no .git directory.
+func LoadFromGitDir(repoPath, gitDir string) (*Store, error) {
+	path := filepath.Join(gitDir, metadataFile)
 
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
-		return NewStore(repoPath), nil
+		store := NewStore(repoPath)
+		store.gitDir = gitDir
+		return store, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read metadata: %w", err)
@@ -24,6 +33,10 @@ func Load(repoPath string) (*Store, error) {
 	if err := json.Unmarshal(data, &store); err != nil {
 		return nil, fmt.Errorf("failed to parse metadata: %w", err)
 	}
+	store.gitDir = gitDir
+	if store.Worktrees == nil {
+		store.Worktrees = make(map[string]*WorktreeMetadata)
+	}
 
 	return &store, nil
 }
@@ -32,7 +45,7 @@ func Load(repoPath string) (*Store, error) {
 func (s *Store) Save() error {
 	s.UpdatedAt = time.Now()
 
-	path := metadataPath(s.RepoPath)
+	path := s.path()
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -51,8 +64,19 @@ func (s *Store) Save() error {
 	return nil
 }
 
+// metadataFile is the name of the metadata file inside the git directory
+const metadataFile = "wtx-meta.json"
+
+// path returns the path to the store's metadata file
+func (s *Store) path() string {
+	if s.gitDir != "" {
+		return filepath.Join(s.gitDir, metadataFile)
+	}
+	return metadataPath(s.RepoPath)
+}
+
 // metadataPath returns the path to the metadata file
 func metadataPath(repoPath string) string {
 	gitDir := filepath.Join(repoPath, ".git")
-	return filepath.Join(gitDir, "wtx-meta.json")
+	return filepath.Join(gitDir, metadataFile)
 }
@@ -72,6 +72,88 @@ func TestRemoveWorktree(t *testing.T) {
 	}
 }
 
+func TestRenameWorktree(t *testing.T) {
+	store := NewStore("/test/repo")
+	created := time.Now().Add(-48 * time.Hour)
+
+	store.Add(&WorktreeMetadata{
+		Name:       "old",
+		Path:       "/test/old",
+		Branch:     "feature/old",
+		CreatedAt:  created,
+		OpenCount:  3,
+		DevCommand: "npm run dev",
+		Ports:      []int{3000},
+	})
+
+	if !store.Rename("old", "new", "/test/new") {
+		t.Fatal("Expected Rename to find the worktree")
+	}
+
+	if _, exists := store.Get("old"); exists {
+		t.Error("Expected old name to be removed")
+	}
+
+	wt, exists := store.Get("new")
+	if !exists {
+		t.Fatal("Expected worktree under new name")
+	}
+	if wt.Name != "new" || wt.Path != "/test/new" {
+		t.Errorf("Expected name/path to be updated, got %s %s", wt.Name, wt.Path)
+	}
+	if !wt.CreatedAt.Equal(created) || wt.OpenCount != 3 || wt.DevCommand != "npm run dev" || len(wt.Ports) != 1 {
+		t.Errorf("Expected history to survive rename, got %+v", wt)
+	}
+
+	if store.Rename("missing", "other", "/test/other") {
+		t.Error("Expected Rename of unknown worktree to return false")
+	}
+
+	// Another worktree's entry is never overwritten
+	store.Add(&WorktreeMetadata{Name: "other", Path: "/test/other", OpenCount: 7})
+	if store.Rename("new", "other", "/test/elsewhere/other") {
+		t.Error("Expected Rename onto an existing name to return false")
+	}
+	if other, _ := store.Get("other"); other.OpenCount != 7 || other.Path != "/test/other" {
+		t.Errorf("Expected existing entry to be kept, got %+v", other)
+	}
+	if _, exists := store.Get("new"); !exists {
+		t.Error("Expected refused rename to keep the original entry")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	store := NewStore("/test/repo")
+	store.Add(&WorktreeMetadata{Name: "kept", Path: "/test/kept"})
+	store.Add(&WorktreeMetadata{Name: "moved", Path: "/test/old-location"})
+	store.Add(&WorktreeMetadata{Name: "renamed", Path: "/test/renamed", OpenCount: 5})
+	store.Add(&WorktreeMetadata{Name: "gone", Path: "/test/gone"})
+
+	removed, updated := store.Reconcile(map[string]string{
+		"kept":    "/test/kept",
+		"moved":   "/test/new-location",
+		"by-hand": "/test/renamed",
+	})
+
+	if len(removed) != 1 || removed[0] != "gone" {
+		t.Errorf("Expected [gone] removed, got %v", removed)
+	}
+	if len(updated) != 2 || updated[0] != "moved" || updated[1] != "renamed" {
+		t.Errorf("Expected [moved renamed] updated, got %v", updated)
+	}
+
+	if wt, _ := store.Get("moved"); wt.Path != "/test/new-location" {
+		t.Errorf("Expected moved path to be updated, got %s", wt.Path)
+	}
+	wt, exists := store.Get("by-hand")
+	if !exists || wt.OpenCount != 5 {
+		t.Errorf("Expected renamed entry to be re-keyed with its history, got %+v", wt)
+	}
+	if _, exists := store.Get("gone"); exists {
+		t.Error("Expected gone entry to be removed")
+	}
+}
+
 func TestTouchWorktree(t *testing.T) {
 	store := NewStore("/test/repo")
 	
@@ -190,3 +272,32 @@ func TestLoadNonExistent(t *testing.T) {
 		t.Errorf("Expected empty store, got %d worktrees", len(store.Worktrees))
 	}
 }
+
+func TestLoadFromGitDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	commonDir := filepath.Join(tmpDir, "repo.git")
+	if err := os.MkdirAll(commonDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadFromGitDir(tmpDir, commonDir)
+	if err != nil {
+		t.Fatalf("LoadFromGitDir failed: %v", err)
+	}
+	store.Add(&WorktreeMetadata{Name: "feature", Path: "/test/feature"})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Failed to save store: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(commonDir, "wtx-meta.json")); err != nil {
+		t.Fatalf("expected metadata in common git dir: %v", err)
+	}
+
+	loaded, err := LoadFromGitDir(tmpDir, commonDir)
+	if err != nil {
+		t.Fatalf("LoadFromGitDir failed: %v", err)
+	}
+	if _, ok := loaded.Get("feature"); !ok {
+		t.Error("Expected worktree to exist in loaded store")
+	}
+}
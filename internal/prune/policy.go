@@ -0,0 +1,259 @@
+// Package prune decides which worktrees are safe and worth removing.
+package prune
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/metadata"
+)
+
+// Policy selects worktrees to prune. A worktree is selected if it matches any
+// enabled criterion, and is only offered if it is also safe to remove: not the
+// main worktree, not locked, clean, and without unpushed commits unless its
+// work is already merged or its upstream is gone. Detached worktrees are
+// checked for commits that aren't on any branch, tag or remote instead.
+type Policy struct {
+	// OlderThan selects worktrees not opened for this long; zero disables it
+	OlderThan time.Duration
+	// Merged selects worktrees whose branch is merged into Base
+	Merged bool
+	// Gone selects worktrees whose upstream branch was deleted
+	Gone bool
+	// Detached selects worktrees created detached with wtx add --at, which
+	// are ephemeral
+	Detached bool
+	// Base is the branch merges are checked against; empty uses the
+	// repository's default branch
+	Base string
+	// AllowLocked offers locked worktrees too
+	AllowLocked bool
+}
+
+// Candidate is a worktree selected for pruning
+type Candidate struct {
+	Worktree git.Worktree
+	Metadata *metadata.WorktreeMetadata
+	// Reasons lists every criterion the worktree matched
+	Reasons []string
+}
+
+// Skipped is a worktree that matched the policy but isn't safe to remove, or
+// couldn't be checked
+type Skipped struct {
+	Worktree git.Worktree
+	Reasons  []string
+	// Why explains what made it unsafe, e.g. "dirty"
+	Why string
+	// Err is set when the worktree couldn't be checked; Reasons may then be
+	// incomplete
+	Err error
+}
+
+// Result is the outcome of evaluating a policy
+type Result struct {
+	Candidates []Candidate
+	Skipped    []Skipped
+}
+
+// Empty reports whether nothing matched the policy
+func (r *Result) Empty() bool {
+	return len(r.Candidates) == 0 && len(r.Skipped) == 0
+}
+
+// Describe returns a short description of the enabled criteria, e.g.
+// "merged, upstream gone or not opened in 30d"
+func (p Policy) Describe() string {
+	var parts []string
+	if p.Merged {
+		parts = append(parts, "merged")
+	}
+	if p.Gone {
+		parts = append(parts, "upstream gone")
+	}
+	if p.Detached {
+		parts = append(parts, "detached")
+	}
+	if p.OlderThan > 0 {
+		parts = append(parts, "not opened in "+FormatAge(p.OlderThan))
+	}
+	switch len(parts) {
+	case 0:
+		return "nothing"
+	case 1:
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
+
+// Evaluate applies the policy to worktrees. A worktree that can't be checked,
+// for example because git status fails on it, is skipped with the error
+// rather than stopping the others from being evaluated.
+func Evaluate(gitMgr *git.Manager, store *metadata.Store, worktrees []git.Worktree, policy Policy, now time.Time) *Result {
+	base := policy.Base
+	if base == "" {
+		base = gitMgr.DefaultBranch()
+	}
+
+	result := &Result{}
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			continue
+		}
+
+		meta, _ := store.Get(wt.Name)
+		if gitMgr.Diagnose(wt) != git.ProblemNone {
+			// Broken worktrees are handled by wtx repair
+			continue
+		}
+
+		reasons, landed, status, err := match(gitMgr, wt, meta, policy, base, now)
+		if err == nil && len(reasons) == 0 {
+			continue
+		}
+
+		why := ""
+		if err == nil {
+			why, err = unsafeReason(gitMgr, wt, status, base, landed, policy.AllowLocked)
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, Skipped{
+				Worktree: wt,
+				Reasons:  reasons,
+				Why:      fmt.Sprintf("couldn't be checked: %v", err),
+				Err:      err,
+			})
+			continue
+		}
+		if why != "" {
+			result.Skipped = append(result.Skipped, Skipped{Worktree: wt, Reasons: reasons, Why: why})
+			continue
+		}
+
+		result.Candidates = append(result.Candidates, Candidate{Worktree: wt, Metadata: meta, Reasons: reasons})
+	}
+
+	return result
+}
+
+// match returns the criteria a worktree matches. landed reports whether its
+// work is merged or its upstream gone, and status is set if it was needed.
+func match(gitMgr *git.Manager, wt git.Worktree, meta *metadata.WorktreeMetadata, policy Policy, base string, now time.Time) (reasons []string, landed bool, status *git.Status, err error) {
+	if policy.Gone {
+		if status, err = gitMgr.GetStatus(wt.Path); err != nil {
+			return nil, false, nil, err
+		}
+	}
+
+	if policy.Merged && wt.Branch != "" && wt.Branch != base {
+		merged, err := gitMgr.IsMerged(wt.Branch, base)
+		if err != nil {
+			return nil, false, status, err
+		}
+		if merged {
+			landed = true
+			reasons = append(reasons, fmt.Sprintf("merged into %s", base))
+		}
+	}
+	if status != nil && status.UpstreamGone {
+		landed = true
+		reasons = append(reasons, "upstream gone")
+	}
+	if policy.Detached && wt.Detached && meta != nil && meta.DetachedAt != "" {
+		reasons = append(reasons, "detached at "+meta.DetachedAt)
+	}
+	if policy.OlderThan > 0 && meta != nil {
+		if age := now.Sub(meta.LastOpened); age > policy.OlderThan {
+			reasons = append(reasons, "not opened in "+FormatAge(age))
+		}
+	}
+
+	return reasons, landed, status, nil
+}
+
+// unsafeReason returns why a selected worktree must not be removed, or "".
+// Merged and gone branches may have commits that exist nowhere else in their
+// original form (squash merges), so the unpushed check is waived for them.
+func unsafeReason(gitMgr *git.Manager, wt git.Worktree, status *git.Status, base string, landed, allowLocked bool) (string, error) {
+	if wt.Locked && !allowLocked {
+		if wt.LockReason != "" {
+			return fmt.Sprintf("locked (%s)", wt.LockReason), nil
+		}
+		return "locked", nil
+	}
+
+	if status == nil {
+		s, err := gitMgr.GetStatus(wt.Path)
+		if err != nil {
+			return "", err
+		}
+		status = s
+	}
+	if status.Operation != git.OperationNone {
+		return fmt.Sprintf("%s in progress", status.Operation), nil
+	}
+	if !status.Clean {
+		return "dirty", nil
+	}
+
+	if !landed && wt.Branch != "" {
+		count, err := gitMgr.UnpushedCount(wt.Branch, base)
+		if err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return fmt.Sprintf("%d unpushed commit(s)", count), nil
+		}
+	}
+	if wt.Detached {
+		// Tags and other branches count: a checkout of a release tag has
+		// nothing to lose even if the tag was never pushed
+		count, err := gitMgr.UnreferencedCount(wt.Head)
+		if err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return fmt.Sprintf("%d commit(s) not on any branch", count), nil
+		}
+	}
+
+	return "", nil
+}
+
+// ParseAge parses an age such as "30d", "2w", "12h" or a plain number of days
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("age cannot be empty")
+	}
+
+	unit := 24 * time.Hour
+	number := s
+	switch s[len(s)-1] {
+	case 'h':
+		unit = time.Hour
+		number = s[:len(s)-1]
+	case 'd':
+		number = s[:len(s)-1]
+	case 'w':
+		unit = 7 * 24 * time.Hour
+		number = s[:len(s)-1]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// FormatAge formats a duration in whole days, or hours below one day
+func FormatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
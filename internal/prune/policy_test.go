@@ -0,0 +1,219 @@
+package prune
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/metadata"
+)
+
+func TestEvaluate(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "repo")
+
+	gitIn := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitIn(dir, "add", file)
+		gitIn(dir, "commit", "-q", "-m", file)
+	}
+
+	gitIn(root, "init", "-q", "--bare", "remote.git")
+	gitIn(root, "init", "-q", "-b", "main", "repo")
+	commit(repoPath, "README.md")
+	gitIn(repoPath, "remote", "add", "origin", filepath.Join(root, "remote.git"))
+	gitIn(repoPath, "push", "-q", "-u", "origin", "main")
+
+	mgr := git.NewManager(&git.Repository{Path: repoPath})
+	store := metadata.NewStore(repoPath)
+	now := time.Now()
+
+	add := func(name string, lastOpened time.Time) string {
+		t.Helper()
+		path, err := mgr.Add(name, name, "main")
+		if err != nil {
+			t.Fatalf("Add(%s) failed: %v", name, err)
+		}
+		store.Add(&metadata.WorktreeMetadata{
+			Name:       name,
+			Path:       path,
+			Branch:     name,
+			CreatedAt:  now,
+			LastOpened: lastOpened,
+		})
+		return path
+	}
+
+	// Merged into main
+	merged := add("merged", now)
+	commit(merged, "merged.txt")
+	gitIn(repoPath, "merge", "-q", "--ff-only", "merged")
+
+	// Just created: no commits of its own, so its tip is on main and it
+	// counts as merged, with nothing to lose
+	add("fresh", now)
+
+	// Upstream deleted after the PR merged, with a squash commit on main
+	gone := add("gone", now)
+	commit(gone, "gone.txt")
+	gitIn(gone, "push", "-q", "-u", "origin", "gone")
+	dirtyGone := add("dirty-gone", now)
+	commit(dirtyGone, "dirty-gone.txt")
+	gitIn(dirtyGone, "push", "-q", "-u", "origin", "dirty-gone")
+	if err := os.WriteFile(filepath.Join(dirtyGone, "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(repoPath, "push", "-q", "origin", ":gone", ":dirty-gone")
+	gitIn(repoPath, "fetch", "-q", "--prune")
+
+	// Old, with and without work that exists nowhere else
+	old := now.Add(-60 * 24 * time.Hour)
+	add("old", old)
+	unpushed := add("old-unpushed", old)
+	commit(unpushed, "unpushed.txt")
+	add("old-locked", old)
+	if err := mgr.Lock("old-locked", "release"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Detached at a local tag, with and without commits made on top
+	gitIn(repoPath, "tag", "v1")
+	addDetached := func(name string) string {
+		t.Helper()
+		result, err := mgr.AddWithOptions(git.AddOptions{Name: name, At: "v1"})
+		if err != nil {
+			t.Fatalf("AddWithOptions(%s) failed: %v", name, err)
+		}
+		store.Add(&metadata.WorktreeMetadata{
+			Name:       name,
+			Path:       result.Path,
+			DetachedAt: "v1",
+			CreatedAt:  now,
+			LastOpened: now,
+		})
+		return result.Path
+	}
+	addDetached("release")
+	commit(addDetached("release-work"), "hotfix.txt")
+
+	// git can't read this one, which mustn't stop the others being checked
+	add("old-broken", old)
+	if err := os.WriteFile(filepath.Join(repoPath, ".git", "worktrees", "old-broken", "index"), []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	policy := Policy{OlderThan: 30 * 24 * time.Hour, Merged: true, Gone: true, Detached: true}
+	result := Evaluate(mgr, store, worktrees, policy, now)
+
+	candidates := make(map[string]string)
+	for _, c := range result.Candidates {
+		candidates[c.Worktree.Name] = strings.Join(c.Reasons, ", ")
+	}
+	skipped := make(map[string]string)
+	for _, s := range result.Skipped {
+		skipped[s.Worktree.Name] = s.Why
+		if s.Err != nil {
+			if !strings.HasPrefix(s.Why, "couldn't be checked: ") {
+				t.Errorf("%s: Why = %q for error %v", s.Worktree.Name, s.Why, s.Err)
+			}
+			skipped[s.Worktree.Name] = "error"
+		}
+	}
+
+	wantCandidates := map[string]string{
+		"merged":  "merged into main",
+		"fresh":   "merged into main",
+		"gone":    "upstream gone",
+		"old":     "merged into main, not opened in 60d",
+		"release": "detached at v1",
+	}
+	wantSkipped := map[string]string{
+		"dirty-gone":   "dirty",
+		"old-unpushed": "1 unpushed commit(s)",
+		"old-locked":   "locked (release)",
+		"release-work": "1 commit(s) not on any branch",
+		"old-broken":   "error",
+	}
+
+	if !equalMaps(candidates, wantCandidates) {
+		t.Errorf("candidates = %v, want %v", candidates, wantCandidates)
+	}
+	if !equalMaps(skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", skipped, wantSkipped)
+	}
+
+	// Only the enabled criteria select worktrees
+	result = Evaluate(mgr, store, worktrees, Policy{Merged: true}, now)
+	var names []string
+	for _, c := range result.Candidates {
+		names = append(names, c.Worktree.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "fresh,merged,old" {
+		t.Errorf("merged-only candidates = %v, want [fresh merged old]", names)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30", 30 * 24 * time.Hour},
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if err != nil {
+			t.Errorf("ParseAge(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "d", "0", "-3d", "1y"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("ParseAge(%q) expected error", in)
+		}
+	}
+}
+
+func equalMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/logger"
+)
+
+// CommitsMsg delivers the last commit of every worktree, keyed by HEAD SHA
+type CommitsMsg struct {
+	Commits map[string]*git.CommitInfo
+}
+
+// loadCommitsCmd reads the items' last commits in the background, so
+// resolving the default branch and walking history don't delay the first
+// frame
+func loadCommitsCmd(gitMgr *git.Manager, items []WorktreeItem) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+
+	worktrees := make([]git.Worktree, 0, len(items))
+	for _, item := range items {
+		worktrees = append(worktrees, item.worktree())
+	}
+	return func() tea.Msg {
+		return CommitsMsg{Commits: loadCommits(gitMgr, worktrees)}
+	}
+}
+
+// loadCommits returns the worktrees' last commits, counted against the
+// default branch
+func loadCommits(gitMgr *git.Manager, worktrees []git.Worktree) map[string]*git.CommitInfo {
+	commits, err := gitMgr.CommitInfos(worktrees, gitMgr.DefaultBranch())
+	if err != nil {
+		logger.Warn("failed to read last commits: %v", err)
+	}
+	return commits
+}
+
+// applyCommits attaches the commits a CommitsMsg carries to the items whose
+// HEAD they belong to, in both the item slice and the list
+func applyCommits(l *list.Model, items []WorktreeItem, msg CommitsMsg) {
+	for i := range items {
+		commit, ok := msg.Commits[items[i].Head]
+		if !ok {
+			continue
+		}
+		items[i].Commit = commit
+		l.SetItem(i, items[i])
+	}
+}
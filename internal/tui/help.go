@@ -153,7 +153,9 @@ func GetManageHelp() []KeyHelp {
 	return []KeyHelp{
 		{"c / n", "Create worktree"},
 		{"d / x", "Delete worktree"},
-		{"p", "Prune stale"},
+		{"l", "Lock / unlock worktree"},
+		{"m", "Move / rename worktree"},
+		{"p", "Prune merged, gone or stale"},
 		{"r", "Refresh list"},
 	}
 }
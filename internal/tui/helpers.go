@@ -3,10 +3,13 @@ package tui
 import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/logger"
 	"github.com/darkLord19/wtx/internal/metadata"
 )
 
-// LoadWorktreeItems loads worktrees and their statuses in parallel
+// LoadWorktreeItems loads worktrees and runs git status for each of them in
+// parallel, even those with a fresh cached status, and records the statuses
+// in the cache for the next start. It also reads their last commits.
 // Returns list items and WorktreeItem slice for use in TUI models
 func LoadWorktreeItems(gitMgr *git.Manager, metaStore *metadata.Store) ([]list.Item, []WorktreeItem, error) {
 	worktrees, err := gitMgr.List()
@@ -14,38 +17,109 @@ func LoadWorktreeItems(gitMgr *git.Manager, metaStore *metadata.Store) ([]list.I
 		return nil, nil, err
 	}
 
-	// Fetch statuses in parallel
+	cache := git.LoadStatusCache(gitMgr.GitDir())
 	statuses := gitMgr.GetStatuses(worktrees)
+	for _, wt := range worktrees {
+		if status, ok := statuses[wt.Path]; ok {
+			cache.Put(wt, status)
+		}
+	}
+	cache.Retain(worktrees)
+	if err := cache.Save(); err != nil {
+		logger.Warn("failed to save status cache: %v", err)
+	}
+
+	items, wtItems := buildWorktreeItems(gitMgr, metaStore, worktrees, func(wt git.Worktree) (*git.Status, bool) {
+		return statuses[wt.Path], false
+	})
+
+	commits := loadCommits(gitMgr, worktrees)
+	for i := range wtItems {
+		wtItems[i].Commit = commits[wtItems[i].Head]
+		items[i] = wtItems[i]
+	}
+	return items, wtItems, nil
+}
+
+// LoadCachedWorktreeItems loads worktrees without running git status. Items
+// with a fresh cached status are complete; the rest show their cached
+// status, if any, as a placeholder and are marked pending until a StatusMsg
+// from startStatusStream arrives. Items without a cached status get
+// ahead/behind counts from a single batched git call in the meantime. Last
+// commits are left for loadCommitsCmd.
+func LoadCachedWorktreeItems(gitMgr *git.Manager, metaStore *metadata.Store) ([]list.Item, []WorktreeItem, error) {
+	worktrees, err := gitMgr.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache := git.LoadStatusCache(gitMgr.GitDir())
+	items, wtItems := buildWorktreeItems(gitMgr, metaStore, worktrees, func(wt git.Worktree) (*git.Status, bool) {
+		status, fresh := cache.Lookup(wt)
+		return status, !fresh
+	})
+
+	var tracking map[string]git.Tracking
+	for i := range wtItems {
+		if wtItems[i].Status != nil || wtItems[i].Branch == "" {
+			continue
+		}
+		if tracking == nil {
+			if tracking, err = gitMgr.BranchTracking(); err != nil {
+				logger.Warn("failed to read branch tracking: %v", err)
+				break
+			}
+		}
+		if t, ok := tracking[wtItems[i].Branch]; ok {
+			wtItems[i].Tracking = &t
+			items[i] = wtItems[i]
+		}
+	}
+
+	return items, wtItems, nil
+}
 
+// buildWorktreeItems combines worktrees with their statuses and metadata
+func buildWorktreeItems(gitMgr *git.Manager, metaStore *metadata.Store, worktrees []git.Worktree, statusFor func(git.Worktree) (*git.Status, bool)) ([]list.Item, []WorktreeItem) {
 	items := make([]list.Item, 0, len(worktrees))
 	wtItems := make([]WorktreeItem, 0, len(worktrees))
 
 	for _, wt := range worktrees {
-		status := statuses[wt.Path]
+		status, pending := statusFor(wt)
 		var meta *metadata.WorktreeMetadata
 		if m, ok := metaStore.Get(wt.Name); ok {
 			meta = m
 		}
 
 		item := WorktreeItem{
-			Name:     wt.Name,
-			Path:     wt.Path,
-			Branch:   wt.Branch,
-			Status:   status,
-			Metadata: meta,
-			IsMain:   wt.IsMain,
+			Name:           wt.Name,
+			Path:           wt.Path,
+			Branch:         wt.Branch,
+			Head:           wt.Head,
+			Status:         status,
+			StatusPending:  pending,
+			Metadata:       meta,
+			IsMain:         wt.IsMain,
+			Detached:       wt.Detached,
+			Locked:         wt.Locked,
+			LockReason:     wt.LockReason,
+			Prunable:       wt.Prunable,
+			PrunableReason: wt.PrunableReason,
+			Problem:        gitMgr.Diagnose(wt),
 		}
 
 		items = append(items, item)
 		wtItems = append(wtItems, item)
 	}
 
-	return items, wtItems, nil
+	return items, wtItems
 }
 
 // CreateListModel creates a configured list.Model with standard settings
 func CreateListModel(items []list.Item, title string) list.Model {
 	delegate := list.NewDefaultDelegate()
+	// Title, status line and last commit
+	delegate.SetHeight(3)
 	l := list.New(items, delegate, 0, 0)
 	l.Title = title
 	l.Styles.Title = titleStyle
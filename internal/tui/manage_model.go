@@ -1,17 +1,21 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/darkLord19/wtx/internal/git"
 	"github.com/darkLord19/wtx/internal/metadata"
+	"github.com/darkLord19/wtx/internal/prune"
 	"github.com/darkLord19/wtx/internal/validation"
 )
 
@@ -32,25 +36,51 @@ type ManageModel struct {
 	// Create Form
 	Inputs    [3]textinput.Model // 0: Name, 1: Branch, 2: Base
 	Focus     int
+	// Prepare also initializes submodules and downloads LFS files in the
+	// background, one step at a time; ctrl+p toggles it when CanPrepare
+	// says the main worktree has either
+	Prepare    bool
+	CanPrepare bool
 
 	// Delete Confirmation
 	DeleteTarget *WorktreeItem
 	ForceDelete  bool
+	AllowLocked  bool
+	// DeleteBranch also deletes the branch if it is merged or pushed, or
+	// regardless with ForceDeleteBranch; 'b' cycles through the choices.
+	// DeleteBranchDefault comes from the delete_branch config. Prune mode
+	// starts from it too, and 'b' toggles DeleteBranch there but never forces.
+	DeleteBranch        bool
+	ForceDeleteBranch   bool
+	DeleteBranchDefault bool
+
+	// Lock prompt
+	LockTarget  *WorktreeItem
+	ReasonInput textinput.Model
+
+	// Move Form
+	MoveTarget *WorktreeItem
+	MoveInputs [2]textinput.Model // 0: Name or path, 1: Branch
+	MoveFocus  int
 
 	// Prune Mode
 	StaleItems    []WorktreeItem
 	PruneCursor   int
 	PruneSelected map[int]bool
+	PruneReasons  map[string][]string
 	StaleDays     int
+	// pruneChecking is set while the prune policy is evaluated
+	pruneChecking bool
 
 	// UI
 	Message Message
 	Help    *HelpPanel
+	Spinner spinner.Model
 }
 
 // NewManageModel creates a new ManageModel
 func NewManageModel(gitMgr *git.Manager, metaStore *metadata.Store) (*ManageModel, error) {
-	items, wtItems, err := LoadWorktreeItems(gitMgr, metaStore)
+	items, wtItems, err := LoadCachedWorktreeItems(gitMgr, metaStore)
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +100,25 @@ func NewManageModel(gitMgr *git.Manager, metaStore *metadata.Store) (*ManageMode
 	inputs[1].Width = 40
 
 	inputs[2] = textinput.New()
-	inputs[2].Placeholder = "main"
+	inputs[2].Placeholder = gitMgr.DefaultBranch()
 	inputs[2].CharLimit = 64
 	inputs[2].Width = 30
-	inputs[2].SetValue("main")
+
+	reasonInput := textinput.New()
+	reasonInput.Placeholder = "reason (optional)"
+	reasonInput.CharLimit = 128
+	reasonInput.Width = 40
+
+	var moveInputs [2]textinput.Model
+	moveInputs[0] = textinput.New()
+	moveInputs[0].Placeholder = "new-name or path"
+	moveInputs[0].CharLimit = 256
+	moveInputs[0].Width = 40
+
+	moveInputs[1] = textinput.New()
+	moveInputs[1].Placeholder = "new branch (optional)"
+	moveInputs[1].CharLimit = 64
+	moveInputs[1].Width = 40
 
 	// Initialize Help
 	help := NewHelpPanel()
@@ -87,14 +132,17 @@ func NewManageModel(gitMgr *git.Manager, metaStore *metadata.Store) (*ManageMode
 		Items:         wtItems,
 		Mode:          ManageModeList,
 		Inputs:        inputs,
+		ReasonInput:   reasonInput,
+		MoveInputs:    moveInputs,
 		PruneSelected: make(map[int]bool),
 		StaleDays:     30,
 		Help:          help,
+		Spinner:       newStatusSpinner(),
 	}, nil
 }
 
 func (m *ManageModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(startStatusStream(m.gitMgr, m.Items), loadCommitsCmd(m.gitMgr, m.Items), m.Spinner.Tick)
 }
 
 func (m *ManageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -124,6 +172,29 @@ func (m *ManageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Message.Clear()
 		}
 		return m, nil
+
+	case StatusMsg:
+		applyStatus(&m.List, m.Items, msg)
+		return m, msg.Next()
+
+	case CommitsMsg:
+		applyCommits(&m.List, m.Items, msg)
+		return m, nil
+
+	case PrepareMsg:
+		return m.prepared(msg)
+
+	case PruneEvaluatedMsg:
+		m.enterPruneMode(msg)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		if !applySpinnerFrame(&m.List, m.Items, m.Spinner.View()) {
+			return m, nil
+		}
+		return m, cmd
 	}
 
 	switch m.Mode {
@@ -135,6 +206,10 @@ func (m *ManageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateDelete(msg)
 	case ManageModePrune:
 		return m.updatePrune(msg)
+	case ManageModeLock:
+		return m.updateLock(msg)
+	case ManageModeMove:
+		return m.updateMove(msg)
 	}
 
 	return m, nil
@@ -154,8 +229,16 @@ func (m *ManageModel) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Focus = 0
 			m.Inputs[0].SetValue("")
 			m.Inputs[1].SetValue("")
-			m.Inputs[2].SetValue("main")
+			m.Inputs[2].SetValue("")
+			m.Inputs[2].Placeholder = m.gitMgr.DefaultBranch()
 			m.Inputs[0].Focus()
+			m.Prepare = false
+			m.CanPrepare = false
+			for _, item := range m.Items {
+				if item.IsMain {
+					m.CanPrepare = git.HasSubmodules(item.Path) || m.gitMgr.UsesLFS(item.Path)
+				}
+			}
 			return m, nil
 
 		case "d", "x":
@@ -167,13 +250,28 @@ func (m *ManageModel) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Mode = ManageModeDelete
 				m.DeleteTarget = &i
 				m.ForceDelete = false
+				m.AllowLocked = false
+				m.DeleteBranch = m.DeleteBranchDefault
+				m.ForceDeleteBranch = false
 			}
 			return m, nil
 
-		case "p":
-			m.enterPruneMode()
+		case "l":
+			if i, ok := m.List.SelectedItem().(WorktreeItem); ok {
+				return m.toggleLock(i)
+			}
 			return m, nil
 
+		case "m":
+			if i, ok := m.List.SelectedItem().(WorktreeItem); ok {
+				m.enterMoveMode(i)
+			}
+			return m, nil
+
+		case "p":
+			m.AllowLocked = false
+			return m, m.evaluatePrune()
+
 		case "r":
 			return m.RefreshList()
 
@@ -217,6 +315,12 @@ func (m *ManageModel) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "ctrl+s":
 			return m.createWorktree()
+
+		case "ctrl+p":
+			if m.CanPrepare {
+				m.Prepare = !m.Prepare
+			}
+			return m, nil
 		}
 	}
 
@@ -239,11 +343,83 @@ func (m *ManageModel) updateDelete(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "f":
 			return m.deleteWorktree(true)
+
+		case "u":
+			if m.DeleteTarget != nil && m.DeleteTarget.Locked {
+				m.AllowLocked = true
+				return m.deleteWorktree(m.ForceDelete)
+			}
+
+		case "b":
+			// keep → delete if merged or pushed → force delete → keep
+			switch {
+			case !m.DeleteBranch:
+				m.DeleteBranch = true
+			case !m.ForceDeleteBranch:
+				m.ForceDeleteBranch = true
+			default:
+				m.DeleteBranch = false
+				m.ForceDeleteBranch = false
+			}
+			return m, nil
 		}
 	}
 	return m, nil
 }
 
+func (m *ManageModel) updateLock(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.Mode = ManageModeList
+			m.LockTarget = nil
+			m.ReasonInput.Blur()
+			return m, nil
+
+		case "enter":
+			return m.lockWorktree()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.ReasonInput, cmd = m.ReasonInput.Update(msg)
+	return m, cmd
+}
+
+func (m *ManageModel) updateMove(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.Mode = ManageModeList
+			m.MoveTarget = nil
+			m.blurMoveInputs()
+			return m, nil
+
+		case "tab", "down", "shift+tab", "up":
+			m.MoveFocus = (m.MoveFocus + 1) % 2
+			m.updateMoveFocus()
+			return m, nil
+
+		case "enter":
+			if m.MoveFocus == 0 {
+				m.MoveFocus = 1
+				m.updateMoveFocus()
+				return m, nil
+			}
+			return m.moveWorktree()
+
+		case "ctrl+s":
+			return m.moveWorktree()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.MoveInputs[m.MoveFocus], cmd = m.MoveInputs[m.MoveFocus].Update(msg)
+	return m, cmd
+}
+
 func (m *ManageModel) updatePrune(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -251,6 +427,7 @@ func (m *ManageModel) updatePrune(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "q":
 			m.Mode = ManageModeList
 			m.StaleItems = nil
+			m.pruneChecking = false
 			return m, nil
 
 		case "up", "k":
@@ -276,6 +453,14 @@ func (m *ManageModel) updatePrune(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.PruneSelected[i] = false
 			}
 
+		case "b":
+			m.DeleteBranch = !m.DeleteBranch
+
+		case "u":
+			// Like wtx prune --allow-locked
+			m.AllowLocked = !m.AllowLocked
+			return m, m.evaluatePrune()
+
 		case "enter":
 			return m.executePrune()
 		}
@@ -285,9 +470,13 @@ func (m *ManageModel) updatePrune(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // Actions
 
+// RefreshList reloads the worktrees, keeping any message about the action
+// that triggered it
 func (m *ManageModel) RefreshList() (tea.Model, tea.Cmd) {
-	m.Message = NewInfoMessage("Refreshing...")
-	return m, fetchWorktreesCmd(m.gitMgr, m.metaStore)
+	if m.Message.IsEmpty() {
+		m.Message = NewInfoMessage("Refreshing...")
+	}
+	return m, refreshWorktreesCmd(m.gitMgr, m.metaStore)
 }
 
 func (m *ManageModel) createWorktree() (tea.Model, tea.Cmd) {
@@ -298,9 +487,6 @@ func (m *ManageModel) createWorktree() (tea.Model, tea.Cmd) {
 	if branch == "" {
 		branch = name
 	}
-	if base == "" {
-		base = "main"
-	}
 
 	// Validation
 	validator := validation.NewWorktreeValidator()
@@ -313,7 +499,7 @@ func (m *ManageModel) createWorktree() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	path, err := m.gitMgr.Add(name, branch, base)
+	result, err := m.gitMgr.AddWithOptions(git.AddOptions{Name: name, Branch: branch, Base: base})
 	if err != nil {
 		m.SetMessage(fmt.Sprintf("Failed to create: %v", err), true)
 		return m, nil
@@ -321,7 +507,7 @@ func (m *ManageModel) createWorktree() (tea.Model, tea.Cmd) {
 
 	meta := &metadata.WorktreeMetadata{
 		Name:       name,
-		Path:       path,
+		Path:       result.Path,
 		Branch:     branch,
 		CreatedAt:  time.Now(),
 		LastOpened: time.Now(),
@@ -333,9 +519,25 @@ func (m *ManageModel) createWorktree() (tea.Model, tea.Cmd) {
 
 	m.Mode = ManageModeList
 	m.blurInputs()
-	m.SetMessage(fmt.Sprintf("✓ Created worktree: %s", name), false)
+	var steps []prepareStep
+	if m.Prepare {
+		steps = prepareSteps(m.gitMgr, result)
+	}
+	message := fmt.Sprintf("✓ Created worktree: %s", name)
+	summary, failed := addSummary(result, len(steps) > 0)
+	if summary != "" {
+		message += "; " + summary
+	}
+	if len(steps) > 0 {
+		message += "; " + steps[0].running
+	}
+	m.SetMessage(message, failed)
 
-	return m.RefreshList()
+	model, refresh := m.RefreshList()
+	if len(steps) > 0 {
+		return model, tea.Batch(refresh, runPrepareStep(name, steps, nil, false))
+	}
+	return model, refresh
 }
 
 func (m *ManageModel) deleteWorktree(force bool) (tea.Model, tea.Cmd) {
@@ -345,6 +547,12 @@ func (m *ManageModel) deleteWorktree(force bool) (tea.Model, tea.Cmd) {
 
 	name := m.DeleteTarget.Name
 
+	// Locked worktrees need an explicit unlock (u) on the confirm screen
+	if m.DeleteTarget.Locked && !m.AllowLocked {
+		m.SetMessage(fmt.Sprintf("Worktree %s is locked%s", name, lockReasonSuffix(m.DeleteTarget.LockReason)), true)
+		return m, nil
+	}
+
 	if !force {
 		clean, err := m.gitMgr.IsClean(m.DeleteTarget.Path)
 		if err != nil {
@@ -360,7 +568,7 @@ func (m *ManageModel) deleteWorktree(force bool) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if err := m.gitMgr.Remove(name, force); err != nil {
+	if err := m.gitMgr.UnlockAndRemove(m.DeleteTarget.worktree(), force); err != nil {
 		m.SetMessage(fmt.Sprintf("Failed to remove: %v", err), true)
 		m.Mode = ManageModeList
 		m.DeleteTarget = nil
@@ -372,63 +580,347 @@ func (m *ManageModel) deleteWorktree(force bool) (tea.Model, tea.Cmd) {
 		m.SetMessage(fmt.Sprintf("Warning: metadata save failed: %v", err), true)
 	}
 
+	message := fmt.Sprintf("✓ Removed worktree: %s", name)
+	branchFailed := false
+	if branch := m.DeleteTarget.Branch; m.DeleteBranch && branch != "" {
+		var unsafe *git.UnsafeBranchError
+		err := m.gitMgr.DeleteBranch(branch, m.gitMgr.DefaultBranch(), m.ForceDeleteBranch)
+		switch {
+		case errors.As(err, &unsafe):
+			message += fmt.Sprintf(" (kept branch %s: %s)", branch, unsafe.Reason)
+		case err != nil:
+			message += fmt.Sprintf(" (failed to delete branch: %v)", err)
+			branchFailed = true
+		default:
+			message += fmt.Sprintf(" and branch %s", branch)
+		}
+	}
+
 	m.Mode = ManageModeList
 	m.DeleteTarget = nil
-	m.SetMessage(fmt.Sprintf("✓ Removed worktree: %s", name), false)
+	m.SetMessage(message, branchFailed)
 
 	return m.RefreshList()
 }
 
-func (m *ManageModel) enterPruneMode() {
-	staleNames := m.metaStore.GetStale(m.StaleDays)
+func (m *ManageModel) enterMoveMode(item WorktreeItem) {
+	if item.IsMain {
+		m.SetMessage("Cannot move main worktree", true)
+		return
+	}
+	if item.Locked {
+		m.SetMessage(fmt.Sprintf("Worktree %s is locked%s", item.Name, lockReasonSuffix(item.LockReason)), true)
+		return
+	}
+
+	m.Mode = ManageModeMove
+	m.MoveTarget = &item
+	m.MoveFocus = 0
+	m.MoveInputs[0].SetValue(item.Name)
+	m.MoveInputs[1].SetValue(item.Branch)
+	if item.Detached {
+		m.MoveInputs[1].SetValue("")
+	}
+	m.updateMoveFocus()
+}
+
+func (m *ManageModel) moveWorktree() (tea.Model, tea.Cmd) {
+	if m.MoveTarget == nil {
+		return m, nil
+	}
+
+	target := *m.MoveTarget
+	dest := strings.TrimSpace(m.MoveInputs[0].Value())
+	branch := strings.TrimSpace(m.MoveInputs[1].Value())
+	if branch == "" || target.Detached {
+		branch = target.Branch
+	}
+
+	newPath, err := git.MoveDestination(target.Path, dest)
+	if err != nil {
+		m.SetMessage(fmt.Sprintf("Invalid destination: %v", err), true)
+		return m, nil
+	}
+	newName := filepath.Base(newPath)
+
+	// Validation
+	validator := validation.NewWorktreeValidator()
+	if err := validator.ValidateName(newName); err != nil {
+		m.SetMessage(fmt.Sprintf("Invalid name: %v", err), true)
+		return m, nil
+	}
+	if branch != target.Branch {
+		if err := validator.ValidateBranchName(branch); err != nil {
+			m.SetMessage(fmt.Sprintf("Invalid branch: %v", err), true)
+			return m, nil
+		}
+	}
+	if newName != target.Name {
+		// Don't take over another worktree's name or metadata
+		_, taken := m.metaStore.Get(newName)
+		for _, item := range m.Items {
+			if item.Name == newName {
+				taken = true
+			}
+		}
+		if taken {
+			m.SetMessage(fmt.Sprintf("A worktree named '%s' already exists", newName), true)
+			return m, nil
+		}
+	}
+
+	if newPath != target.Path {
+		if err := m.gitMgr.Move(target.Name, newPath); err != nil {
+			m.SetMessage(fmt.Sprintf("Failed to move: %v", err), true)
+			return m, nil
+		}
+	}
+
+	m.Mode = ManageModeList
+	m.MoveTarget = nil
+	m.blurMoveInputs()
+
+	var branchErr error
+	if branch != target.Branch {
+		if branchErr = m.gitMgr.RenameBranch(target.Branch, branch); branchErr != nil {
+			branch = target.Branch
+		}
+	}
+
+	m.metaStore.Rename(target.Name, newName, newPath)
+	if meta, ok := m.metaStore.Get(newName); ok {
+		meta.Branch = branch
+	}
+	if err := m.metaStore.Save(); err != nil {
+		m.SetMessage(fmt.Sprintf("Warning: metadata save failed: %v", err), true)
+	}
+
+	if branchErr != nil {
+		m.SetMessage(fmt.Sprintf("Moved, but failed to rename branch: %v", branchErr), true)
+	} else {
+		m.SetMessage(fmt.Sprintf("✓ Moved worktree: %s → %s", target.Name, newName), false)
+	}
+
+	return m.RefreshList()
+}
 
-	if len(staleNames) == 0 {
-		m.SetMessage(fmt.Sprintf("No stale worktrees found (>%d days)", m.StaleDays), false)
+// prunePolicy returns the policy used by prune mode
+func (m *ManageModel) prunePolicy() prune.Policy {
+	return prune.Policy{
+		OlderThan: time.Duration(m.StaleDays) * 24 * time.Hour,
+		Merged:    true,
+		Gone:      true,
+		Detached:  true,
+		// Locked worktrees are only offered after pressing u
+		AllowLocked: m.AllowLocked,
+	}
+}
+
+// PruneEvaluatedMsg carries the outcome of applying the prune policy, which
+// runs git status and merge checks on every worktree
+type PruneEvaluatedMsg struct {
+	Policy prune.Policy
+	Result *prune.Result
+}
+
+// evaluatePrune returns a command applying the prune policy to the listed
+// worktrees in the background
+func (m *ManageModel) evaluatePrune() tea.Cmd {
+	m.Message = NewInfoMessage("Checking worktrees...")
+	m.pruneChecking = true
+
+	policy := m.prunePolicy()
+	worktrees := make([]git.Worktree, 0, len(m.Items))
+	for _, item := range m.Items {
+		worktrees = append(worktrees, item.worktree())
+	}
+	gitMgr, metaStore := m.gitMgr, m.metaStore
+	return func() tea.Msg {
+		return PruneEvaluatedMsg{
+			Policy: policy,
+			Result: prune.Evaluate(gitMgr, metaStore, worktrees, policy, time.Now()),
+		}
+	}
+}
+
+// enterPruneMode offers the candidates of an evaluated prune policy
+func (m *ManageModel) enterPruneMode(msg PruneEvaluatedMsg) {
+	// Ignore results the user moved on from while they were computed
+	if !m.pruneChecking || msg.Policy.AllowLocked != m.AllowLocked {
+		return
+	}
+	m.pruneChecking = false
+	if m.Mode != ManageModeList && m.Mode != ManageModePrune {
 		return
 	}
+	if m.Message.Text() == "Checking worktrees..." {
+		m.Message.Clear()
+	}
 
-	m.StaleItems = []WorktreeItem{}
+	policy, result := msg.Policy, msg.Result
 	itemMap := make(map[string]WorktreeItem)
 	for _, item := range m.Items {
 		itemMap[item.Name] = item
 	}
 
-	for _, name := range staleNames {
-		if item, ok := itemMap[name]; ok {
-			if !item.IsMain {
-				clean, _ := m.gitMgr.IsClean(item.Path)
-				if clean {
-					m.StaleItems = append(m.StaleItems, item)
-				}
-			}
+	m.StaleItems = []WorktreeItem{}
+	m.PruneReasons = make(map[string][]string)
+	for _, c := range result.Candidates {
+		if item, ok := itemMap[c.Worktree.Name]; ok {
+			m.StaleItems = append(m.StaleItems, item)
+			m.PruneReasons[item.Name] = c.Reasons
 		}
 	}
 
-	if len(m.StaleItems) == 0 {
-		m.SetMessage(fmt.Sprintf("No clean stale worktrees found (>%d days)", m.StaleDays), false)
+	skipped, failed := pruneSkippedSummary(result.Skipped)
+	locked := false
+	for _, s := range result.Skipped {
+		locked = locked || s.Worktree.Locked
+	}
+	// Stay in prune mode when locked worktrees were skipped, so u can offer
+	// them
+	if len(m.StaleItems) == 0 && !locked {
+		m.Mode = ManageModeList
+		if skipped != "" {
+			m.SetMessage(fmt.Sprintf("No worktrees safe to prune (%s); %s", policy.Describe(), skipped), failed)
+			return
+		}
+		m.SetMessage(fmt.Sprintf("No worktrees to prune (%s)", policy.Describe()), false)
 		return
 	}
 
-	m.Mode = ManageModePrune
+	if skipped != "" {
+		m.SetMessage(skipped, failed)
+	}
+
+	if m.Mode != ManageModePrune {
+		m.Mode = ManageModePrune
+		m.DeleteBranch = m.DeleteBranchDefault
+		m.ForceDeleteBranch = false
+	}
 	m.PruneCursor = 0
 	m.PruneSelected = make(map[int]bool)
-	for i := range m.StaleItems {
-		m.PruneSelected[i] = true
+	for i, item := range m.StaleItems {
+		// Locked worktrees have to be selected one by one
+		m.PruneSelected[i] = !item.Locked
+	}
+}
+
+// addSummary describes the files brought into a new worktree from
+// .wtxinclude, naming the ones that were skipped, and points out submodules
+// and LFS files it still needs unless they are being prepared. failed
+// reports whether anything failed.
+func addSummary(result *git.AddResult, preparing bool) (summary string, failed bool) {
+	var parts, skipped []string
+	brought := 0
+	for _, inc := range result.Included {
+		switch inc.Status {
+		case git.IncludeCreated, git.IncludeUpdated:
+			brought++
+		case git.IncludeSkipped:
+			skipped = append(skipped, inc.Path)
+		}
+	}
+	if brought > 0 {
+		parts = append(parts, fmt.Sprintf("included %d path(s) from %s", brought, git.IncludeFile))
+	}
+	if len(skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("skipped %s (differs from the main worktree)", strings.Join(skipped, ", ")))
 	}
+	if result.IncludeErr != nil {
+		parts = append(parts, result.IncludeErr.Error())
+	}
+	if result.HasSubmodules && !preparing {
+		parts = append(parts, "has submodules: run 'git submodule update --init --recursive' or create with ctrl+p")
+	}
+	if result.UsesLFS && !preparing {
+		parts = append(parts, "uses Git LFS: run 'git lfs pull' or create with ctrl+p")
+	}
+	return strings.Join(parts, "; "), result.IncludeErr != nil
+}
+
+// pruneSkippedSummary describes the worktrees prune skipped, naming the ones
+// that couldn't be checked. failed reports whether there were any.
+func pruneSkippedSummary(skipped []prune.Skipped) (summary string, failed bool) {
+	var parts, errs, locked []string
+	unsafe := 0
+	for _, s := range skipped {
+		switch {
+		case s.Err != nil:
+			errs = append(errs, fmt.Sprintf("%s (%v)", s.Worktree.Name, s.Err))
+		case s.Worktree.Locked:
+			locked = append(locked, s.Worktree.Name+lockReasonSuffix(s.Worktree.LockReason))
+		default:
+			unsafe++
+		}
+	}
+	if unsafe > 0 {
+		parts = append(parts, fmt.Sprintf("%d worktree(s) skipped: dirty or unpushed", unsafe))
+	}
+	if len(locked) > 0 {
+		parts = append(parts, fmt.Sprintf("skipped locked %s (u to include)", strings.Join(locked, ", ")))
+	}
+	if len(errs) > 0 {
+		parts = append(parts, "couldn't check "+strings.Join(errs, ", "))
+	}
+	return strings.Join(parts, "; "), len(errs) > 0
 }
 
 func (m *ManageModel) executePrune() (tea.Model, tea.Cmd) {
 	removed := 0
+	var locked, deletedBranches, keptBranches []string
+	var failed []string
+	branchFailed := false
+	base := m.gitMgr.DefaultBranch()
+
+	// The items are a snapshot, so read the lock state again
+	worktrees, err := m.gitMgr.List()
+	if err != nil {
+		m.SetMessage(fmt.Sprintf("Failed to list worktrees: %v", err), true)
+		return m, nil
+	}
+	current := make(map[string]git.Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		current[wt.Path] = wt
+	}
+
 	for i, item := range m.StaleItems {
 		if !m.PruneSelected[i] {
 			continue
 		}
 
-		if err := m.gitMgr.Remove(item.Name, false); err != nil {
+		// Never prune locked worktrees unless allowed, even if they were
+		// locked after selection
+		wt, ok := current[item.Path]
+		if !ok {
+			continue
+		}
+		if wt.Locked && !m.AllowLocked {
+			locked = append(locked, wt.Name+lockReasonSuffix(wt.LockReason))
+			continue
+		}
+
+		if err := m.gitMgr.UnlockAndRemove(wt, false); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", item.Name, err))
 			continue
 		}
 		m.metaStore.Remove(item.Name)
 		removed++
+
+		// Only branches that are merged or pushed; prune never forces
+		if m.DeleteBranch && item.Branch != "" {
+			var unsafe *git.UnsafeBranchError
+			err := m.gitMgr.DeleteBranch(item.Branch, base, false)
+			switch {
+			case errors.As(err, &unsafe):
+				keptBranches = append(keptBranches, fmt.Sprintf("%s (%s)", item.Branch, unsafe.Reason))
+			case err != nil:
+				keptBranches = append(keptBranches, fmt.Sprintf("%s (%v)", item.Branch, err))
+				branchFailed = true
+			default:
+				deletedBranches = append(deletedBranches, item.Branch)
+			}
+		}
 	}
 
 	if err := m.metaStore.Save(); err != nil {
@@ -437,18 +929,98 @@ func (m *ManageModel) executePrune() (tea.Model, tea.Cmd) {
 
 	m.Mode = ManageModeList
 	m.StaleItems = nil
-	m.SetMessage(fmt.Sprintf("✓ Removed %d worktree(s)", removed), false)
+	message := fmt.Sprintf("✓ Removed %d worktree(s)", removed)
+	if len(locked) > 0 {
+		message += fmt.Sprintf("; skipped locked %s", strings.Join(locked, ", "))
+	}
+	if len(failed) > 0 {
+		message += fmt.Sprintf("; failed to remove %s", strings.Join(failed, ", "))
+	}
+	if len(deletedBranches) > 0 {
+		message += fmt.Sprintf("; deleted branch(es) %s", strings.Join(deletedBranches, ", "))
+	}
+	if len(keptBranches) > 0 {
+		message += fmt.Sprintf("; kept branch(es) %s", strings.Join(keptBranches, ", "))
+	}
+	m.SetMessage(message, branchFailed || len(failed) > 0)
 
 	return m.RefreshList()
 }
 
+func (m *ManageModel) toggleLock(item WorktreeItem) (tea.Model, tea.Cmd) {
+	if item.IsMain {
+		m.SetMessage("Cannot lock main worktree", true)
+		return m, nil
+	}
+
+	if item.Locked {
+		if err := m.gitMgr.Unlock(item.Name); err != nil {
+			m.SetMessage(fmt.Sprintf("Failed to unlock: %v", err), true)
+			return m, nil
+		}
+		m.SetMessage(fmt.Sprintf("✓ Unlocked worktree: %s", item.Name), false)
+		return m.RefreshList()
+	}
+
+	m.Mode = ManageModeLock
+	m.LockTarget = &item
+	m.ReasonInput.SetValue("")
+	m.ReasonInput.Focus()
+	return m, nil
+}
+
+func (m *ManageModel) lockWorktree() (tea.Model, tea.Cmd) {
+	if m.LockTarget == nil {
+		return m, nil
+	}
+
+	name := m.LockTarget.Name
+	reason := strings.TrimSpace(m.ReasonInput.Value())
+
+	m.Mode = ManageModeList
+	m.LockTarget = nil
+	m.ReasonInput.Blur()
+
+	if err := m.gitMgr.Lock(name, reason); err != nil {
+		m.SetMessage(fmt.Sprintf("Failed to lock: %v", err), true)
+		return m, nil
+	}
+
+	m.SetMessage(fmt.Sprintf("🔒 Locked worktree: %s", name), false)
+	return m.RefreshList()
+}
+
 // Helpers
 
+// lockReasonSuffix formats a lock reason for messages
+func lockReasonSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(": %s", reason)
+}
+
 func (m *ManageModel) updateFocus() {
 	m.blurInputs()
 	m.Inputs[m.Focus].Focus()
 }
 
+func (m *ManageModel) updateMoveFocus() {
+	for i := range m.MoveInputs {
+		if i == m.MoveFocus {
+			m.MoveInputs[i].Focus()
+		} else {
+			m.MoveInputs[i].Blur()
+		}
+	}
+}
+
+func (m *ManageModel) blurMoveInputs() {
+	for i := range m.MoveInputs {
+		m.MoveInputs[i].Blur()
+	}
+}
+
 func (m *ManageModel) blurInputs() {
 	for i := range m.Inputs {
 		m.Inputs[i].Blur()
@@ -485,6 +1057,10 @@ func (m *ManageModel) View() string {
 		breadcrumb.Add("Delete")
 	case ManageModePrune:
 		breadcrumb.Add("Prune")
+	case ManageModeLock:
+		breadcrumb.Add("Lock")
+	case ManageModeMove:
+		breadcrumb.Add("Move")
 	}
 
 	// Only show breadcrumb if we are in submode (optional, but consistent)
@@ -501,10 +1077,14 @@ func (m *ManageModel) View() string {
 		b.WriteString(m.viewDeleteConfirm())
 	case ManageModePrune:
 		b.WriteString(m.viewPruneMode())
+	case ManageModeLock:
+		b.WriteString(m.viewLockPrompt())
+	case ManageModeMove:
+		b.WriteString(m.viewMoveForm())
 	default:
 		b.WriteString(m.List.View())
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render("c create • d delete • p prune • r refresh • q quit"))
+		b.WriteString(helpStyle.Render("c create • d delete • l lock • m move • p prune • r refresh • q quit"))
 	}
 
 	if !m.Message.IsEmpty() {
@@ -534,6 +1114,15 @@ func (m *ManageModel) viewCreateForm() string {
 		b.WriteString(fmt.Sprintf("%s\n  %s\n\n", labelStyle.Render(label), m.Inputs[i].View()))
 	}
 
+	if m.CanPrepare {
+		prepare := "off"
+		if m.Prepare {
+			prepare = "on"
+		}
+		b.WriteString(fmt.Sprintf("  Initialize submodules and LFS files: %s\n\n", prepare))
+		b.WriteString(helpStyle.Render("tab next • ctrl+p toggle submodules/LFS • ctrl+s create • esc cancel"))
+		return b.String()
+	}
 	b.WriteString(helpStyle.Render("tab next • ctrl+s create • esc cancel"))
 	return b.String()
 }
@@ -548,9 +1137,27 @@ func (m *ManageModel) viewDeleteConfirm() string {
 	if m.DeleteTarget != nil {
 		b.WriteString(fmt.Sprintf("Worktree: %s\n", lipgloss.NewStyle().Bold(true).Render(m.DeleteTarget.Name)))
 		b.WriteString(fmt.Sprintf("Path:     %s\n", m.DeleteTarget.Path))
-		b.WriteString(fmt.Sprintf("Branch:   %s\n\n", m.DeleteTarget.Branch))
+		b.WriteString(fmt.Sprintf("Branch:   %s\n", m.DeleteTarget.Branch))
+		if m.DeleteTarget.Branch != "" {
+			switch {
+			case m.ForceDeleteBranch:
+				b.WriteString(dirtyStyle.Render("          delete branch, even with unmerged commits"))
+			case m.DeleteBranch:
+				b.WriteString(warningStyle.Render("          delete branch if merged or pushed"))
+			default:
+				b.WriteString(helpStyle.Render("          keep branch"))
+			}
+			b.WriteString(helpStyle.Render("  (b to change)"))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 
-		if m.ForceDelete {
+		if m.DeleteTarget.Locked && !m.AllowLocked {
+			b.WriteString(warningStyle.Bold(true).Render(
+				fmt.Sprintf("🔒 This worktree is locked%s", lockReasonSuffix(m.DeleteTarget.LockReason))))
+			b.WriteString("\n\n")
+			b.WriteString("u unlock & delete • n/esc cancel\n")
+		} else if m.ForceDelete {
 			b.WriteString(lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#FF6B6B")).
 				Bold(true).
@@ -566,10 +1173,52 @@ func (m *ManageModel) viewDeleteConfirm() string {
 	return b.String()
 }
 
+func (m *ManageModel) viewLockPrompt() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("🔒 Lock Worktree")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	if m.LockTarget != nil {
+		b.WriteString(fmt.Sprintf("Worktree: %s\n\n", lipgloss.NewStyle().Bold(true).Render(m.LockTarget.Name)))
+		b.WriteString("Reason:\n  ")
+		b.WriteString(m.ReasonInput.View())
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("enter lock • esc cancel"))
+	return b.String()
+}
+
+func (m *ManageModel) viewMoveForm() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("📦 Move Worktree")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	if m.MoveTarget != nil {
+		b.WriteString(fmt.Sprintf("Worktree: %s\n", lipgloss.NewStyle().Bold(true).Render(m.MoveTarget.Name)))
+		b.WriteString(fmt.Sprintf("Path:     %s\n\n", m.MoveTarget.Path))
+
+		labels := []string{"New name or path:", "Branch:"}
+		for i, label := range labels {
+			b.WriteString(label)
+			b.WriteString("\n  ")
+			b.WriteString(m.MoveInputs[i].View())
+			b.WriteString("\n\n")
+		}
+	}
+
+	b.WriteString(helpStyle.Render("tab next • enter move • esc cancel"))
+	return b.String()
+}
+
 func (m *ManageModel) viewPruneMode() string {
 	var b strings.Builder
 
-	title := titleStyle.Render(fmt.Sprintf("🧹 Prune Stale Worktrees (>%d days)", m.StaleDays))
+	title := titleStyle.Render(fmt.Sprintf("🧹 Prune Worktrees (%s)", m.prunePolicy().Describe()))
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
@@ -589,13 +1238,16 @@ func (m *ManageModel) viewPruneMode() string {
 			name = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Render(name)
 		}
 
-		lastOpened := ""
-		if item.Metadata != nil {
-			lastOpened = fmt.Sprintf(" (last: %s)", item.Metadata.LastOpened.Format("2006-01-02"))
+		reasons := ""
+		if r := m.PruneReasons[item.Name]; len(r) > 0 {
+			reasons = fmt.Sprintf(" (%s)", strings.Join(r, ", "))
+		}
+		if item.Locked {
+			reasons += fmt.Sprintf(" 🔒 locked%s", lockReasonSuffix(item.LockReason))
 		}
 
 		b.WriteString(fmt.Sprintf("%s%s %s%s\n", cursor, checkbox, name,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(lastOpened)))
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(reasons)))
 	}
 
 	selected := 0
@@ -605,8 +1257,26 @@ func (m *ManageModel) viewPruneMode() string {
 		}
 	}
 
+	if len(m.StaleItems) == 0 {
+		b.WriteString(helpStyle.Render("No unlocked worktrees to prune; press u to include locked ones\n"))
+	}
+
 	b.WriteString(fmt.Sprintf("\n%d of %d selected\n", selected, len(m.StaleItems)))
-	b.WriteString(helpStyle.Render("\n↑/↓ navigate • space toggle • a all • n none • enter delete • esc cancel"))
+	if m.DeleteBranch {
+		b.WriteString(warningStyle.Render("Branches: delete if merged or pushed"))
+	} else {
+		b.WriteString(helpStyle.Render("Branches: keep"))
+	}
+	b.WriteString(helpStyle.Render("  (b to change)"))
+	b.WriteString("\n")
+	if m.AllowLocked {
+		b.WriteString(warningStyle.Render("Locked worktrees: offered"))
+	} else {
+		b.WriteString(helpStyle.Render("Locked worktrees: skipped"))
+	}
+	b.WriteString(helpStyle.Render("  (u to change)"))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("\n↑/↓ navigate • space toggle • a all • n none • b branches • u locked • enter delete • esc cancel"))
 
 	return b.String()
 }
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestExecutePruneReportsBranches(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) {
+		t.Helper()
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("worktree", "add", "-q", "-b", "merged", filepath.Join(dir, "wt-merged"))
+	git("worktree", "add", "-q", "-b", "unmerged", filepath.Join(dir, "wt-unmerged"))
+	git("-C", filepath.Join(dir, "wt-unmerged"), "commit", "-q", "--allow-empty", "-m", "Work")
+
+	m, err := NewManageModel(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	m.StaleItems = []WorktreeItem{
+		{Name: "wt-merged", Path: filepath.Join(dir, "wt-merged"), Branch: "merged"},
+		{Name: "wt-unmerged", Path: filepath.Join(dir, "wt-unmerged"), Branch: "unmerged"},
+	}
+	m.PruneSelected = map[int]bool{0: true, 1: true}
+	m.DeleteBranch = true
+
+	if view := m.viewPruneMode(); !strings.Contains(view, "Branches: delete if merged or pushed") {
+		t.Errorf("prune view doesn't say branches will be deleted:\n%s", view)
+	}
+
+	m.executePrune()
+
+	message := m.Message.Text()
+	for _, want := range []string{"Removed 2 worktree(s)", "deleted branch(es) merged", "kept branch(es) unmerged (isn't merged"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message %q doesn't contain %q", message, want)
+		}
+	}
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "-q", "refs/heads/unmerged").Run(); err != nil {
+		t.Errorf("unmerged branch was deleted")
+	}
+}
+
+func TestCreateWorktreeReportsIncludes(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	for file, content := range map[string]string{
+		".gitignore":  ".env\n",
+		".env":        "PORT=3000\n",
+		".wtxinclude": ".env\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewManageModel(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	m.Inputs[0].SetValue("feature")
+	m.createWorktree()
+
+	if message := m.Message.Text(); !strings.Contains(message, "Created worktree: feature; included 1 path(s) from .wtxinclude") {
+		t.Errorf("unexpected message %q", message)
+	}
+	meta, ok := metaStore.Get("feature")
+	if !ok {
+		t.Fatal("no metadata for the new worktree")
+	}
+	defer os.RemoveAll(meta.Path)
+	if _, err := os.Stat(filepath.Join(meta.Path, ".env")); err != nil {
+		t.Errorf(".env wasn't included: %v", err)
+	}
+}
+
+func TestExecutePruneRechecksLocks(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "wt-stale")
+	if output, err := exec.Command("git", "-C", dir, "worktree", "add", "-q", "-b", "stale", path).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, output)
+	}
+
+	m, err := NewManageModel(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	m.StaleItems = []WorktreeItem{{Name: "wt-stale", Path: path, Branch: "stale"}}
+	m.PruneSelected = map[int]bool{0: true}
+
+	// Locked after the prune list was built
+	if err := gitMgr.Lock(path, "in use"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	m.executePrune()
+
+	if message := m.Message.Text(); !strings.Contains(message, "Removed 0 worktree(s); skipped locked wt-stale: in use") {
+		t.Errorf("unexpected message %q", message)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("locked worktree was removed: %v", err)
+	}
+
+	// u offers it, unselected, like wtx prune --allow-locked
+	m.Items = []WorktreeItem{{Name: "wt-stale", Path: path, Branch: "stale", Locked: true, LockReason: "in use"}}
+	m.StaleDays = 0
+	_, cmd := m.updateList(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if m.Mode != ManageModeList || m.Message.Text() != "Checking worktrees..." {
+		t.Fatalf("expected the policy to be evaluated in the background, got mode %v and %q", m.Mode, m.Message.Text())
+	}
+	m.Update(cmd())
+	if m.Mode != ManageModePrune || len(m.StaleItems) != 0 {
+		t.Fatalf("expected an empty prune list offering locked worktrees, got mode %v with %d", m.Mode, len(m.StaleItems))
+	}
+	if message := m.Message.Text(); !strings.Contains(message, "skipped locked wt-stale: in use") {
+		t.Errorf("unexpected message %q", message)
+	}
+	_, cmd = m.updatePrune(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m.Update(cmd())
+	if len(m.StaleItems) != 1 || m.PruneSelected[0] {
+		t.Fatalf("expected the locked worktree to be offered unselected, got %+v", m.StaleItems)
+	}
+
+	m.PruneSelected[0] = true
+	m.executePrune()
+	if message := m.Message.Text(); !strings.Contains(message, "Removed 1 worktree(s)") {
+		t.Errorf("unexpected message %q", message)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("locked worktree wasn't removed with u: %v", err)
+	}
+}
+
+func TestCreateWorktreePreparesSubmodules(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	// Local submodule URLs need the file protocol, which git disables by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	lib := t.TempDir()
+	for _, args := range [][]string{
+		{"-C", lib, "init", "-q"},
+		{"-C", lib, "-c", "user.name=Lib", "-c", "user.email=lib@example.com", "commit", "-q", "--allow-empty", "-m", "lib"},
+		{"-C", dir, "submodule", "add", "-q", lib, "vendor/lib"},
+		{"-C", dir, "commit", "-q", "-m", "Add submodule"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	m, err := NewManageModel(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.CanPrepare || !m.Prepare {
+		t.Fatalf("expected ctrl+p to turn on preparing, got CanPrepare=%v Prepare=%v", m.CanPrepare, m.Prepare)
+	}
+
+	m.Inputs[0].SetValue("feature")
+	_, cmd := m.createWorktree()
+	if message := m.Message.Text(); !strings.HasSuffix(message, "initializing submodules...") {
+		t.Errorf("expected a status line for the submodule step, got %q", message)
+	}
+	meta, ok := metaStore.Get("feature")
+	if !ok {
+		t.Fatal("no metadata for the new worktree")
+	}
+	defer os.RemoveAll(meta.Path)
+
+	var prepared *PrepareMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(PrepareMsg); ok {
+			prepared = &msg
+		}
+	}
+	if prepared == nil {
+		t.Fatal("expected the submodule step to run in the background")
+	}
+	m.Update(*prepared)
+
+	if message := m.Message.Text(); message != "feature: ✓ submodules initialized" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if _, err := os.Stat(filepath.Join(meta.Path, "vendor", "lib", ".git")); err != nil {
+		t.Errorf("submodule wasn't checked out: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -58,6 +59,9 @@ type managerModel struct {
 
 	// Help
 	help *HelpPanel
+
+	// Spinner shown on rows whose status is still loading
+	spinner spinner.Model
 }
 
 // ManageMode represents the current mode in the manage tab
@@ -68,6 +72,8 @@ const (
 	ManageModeCreate
 	ManageModeDelete
 	ManageModePrune
+	ManageModeLock
+	ManageModeMove
 )
 
 // WorktreeListMsg contains the list of worktrees fetched asynchronously
@@ -78,8 +84,8 @@ type WorktreeListMsg struct {
 
 // NewManagerModel creates a new manager TUI model
 func NewManagerModel(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config.Config, edDetector *editor.Detector) (*managerModel, error) {
-	// Load worktrees
-	items, wtItems, err := LoadWorktreeItems(gitMgr, metaStore)
+	// Load worktrees with cached statuses; Init revalidates them
+	items, wtItems, err := LoadCachedWorktreeItems(gitMgr, metaStore)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -92,6 +98,7 @@ func NewManagerModel(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config
 	if err != nil {
 		return nil, err
 	}
+	manageModel.DeleteBranchDefault = cfg.DeleteBranch
 
 	// Initialize help panel
 	help := NewHelpPanel()
@@ -139,7 +146,7 @@ func NewManagerModel(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config
 		},
 		{
 			Name:        "Worktree Directory",
-			Description: "Where to create worktrees",
+			Description: "Where to create worktrees (path, template with {repo}/{name}/{branch}, or sibling/nested/central)",
 			Type:        SettingWorktreeDir,
 			Value:       cfg.WorktreeDir,
 			Options:     nil,
@@ -165,11 +172,12 @@ func NewManagerModel(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config
 		settingInput:  settingInput,
 		settings:      settings,
 		help:          help,
+		spinner:       newStatusSpinner(),
 	}, nil
 }
 
 func (m *managerModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(startStatusStream(m.gitMgr, m.items), loadCommitsCmd(m.gitMgr, m.items), m.spinner.Tick)
 }
 
 func (m *managerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -209,6 +217,34 @@ func (m *managerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case StatusMsg:
+		applyStatus(&m.worktreeList, m.items, msg)
+		applyStatus(&m.manageModel.List, m.manageModel.Items, msg)
+		return m, msg.Next()
+
+	case CommitsMsg:
+		applyCommits(&m.worktreeList, m.items, msg)
+		applyCommits(&m.manageModel.List, m.manageModel.Items, msg)
+		return m, nil
+
+	case PrepareMsg, PruneEvaluatedMsg:
+		newModel, cmd := m.manageModel.Update(msg)
+		m.manageModel = newModel.(*ManageModel)
+		return m, cmd
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		frame := m.spinner.View()
+		pending := applySpinnerFrame(&m.worktreeList, m.items, frame)
+		if applySpinnerFrame(&m.manageModel.List, m.manageModel.Items, frame) {
+			pending = true
+		}
+		if !pending {
+			return m, nil
+		}
+		return m, cmd
+
 	case tea.KeyMsg:
 		// Clear message on any key
 		if !m.message.IsEmpty() {
@@ -382,7 +418,8 @@ func (m *managerModel) updateSettingEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // Helper methods
 
-func fetchWorktreesCmd(gitMgr *git.Manager, metaStore *metadata.Store) tea.Cmd {
+// refreshWorktreesCmd reloads worktrees with a fresh status for each one
+func refreshWorktreesCmd(gitMgr *git.Manager, metaStore *metadata.Store) tea.Cmd {
 	return func() tea.Msg {
 		_, wtItems, err := LoadWorktreeItems(gitMgr, metaStore)
 		if err != nil {
@@ -427,6 +464,10 @@ func (m *managerModel) saveSettings() {
 		case SettingReuseWindow:
 			m.config.ReuseWindow = setting.Value == "true"
 		case SettingWorktreeDir:
+			if err := git.ValidateLayout(setting.Value); err != nil {
+				m.setMessage(fmt.Sprintf("Invalid worktree directory: %v", err), true)
+				return
+			}
 			m.config.WorktreeDir = setting.Value
 		case SettingAutoStartDev:
 			m.config.AutoStartDev = setting.Value == "true"
@@ -443,6 +484,7 @@ func (m *managerModel) saveSettings() {
 	if err := m.config.Save(); err != nil {
 		m.setMessage(fmt.Sprintf("Failed to save: %v", err), true)
 	} else {
+		m.gitMgr.SetWorktreeDir(m.config.WorktreeDir)
 		m.setMessage("✓ Settings saved!", false)
 	}
 }
@@ -480,6 +522,10 @@ func (m *managerModel) View() string {
 			breadcrumb.Add("Delete")
 		} else if m.manageModel.Mode == ManageModePrune {
 			breadcrumb.Add("Prune")
+		} else if m.manageModel.Mode == ManageModeLock {
+			breadcrumb.Add("Lock")
+		} else if m.manageModel.Mode == ManageModeMove {
+			breadcrumb.Add("Move")
 		}
 	case TabSettings:
 		breadcrumb.Add("Settings")
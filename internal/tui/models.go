@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/darkLord19/wtx/internal/git"
 	"github.com/darkLord19/wtx/internal/metadata"
@@ -12,9 +13,31 @@ type WorktreeItem struct {
 	Name     string
 	Path     string
 	Branch   string
+	Head     string
 	Status   *git.Status
 	Metadata *metadata.WorktreeMetadata
 	IsMain   bool
+
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
+
+	// Problem is set when the worktree directory or its gitdir link is broken
+	Problem git.Problem
+
+	// StatusPending is set while a cached (or missing) status is being
+	// revalidated
+	StatusPending bool
+	// StatusTimedOut is set when git status took too long
+	StatusTimedOut bool
+	// Tracking holds ahead/behind counts shown until the status is known
+	Tracking *git.Tracking
+	// Commit describes the last commit, shown on the item's third line
+	Commit *git.CommitInfo
+
+	spinnerFrame string
 }
 
 // Title returns the primary display text
@@ -29,22 +52,64 @@ func (w WorktreeItem) Description() string {
 	// Branch name
 	if w.Branch != "" {
 		desc += fmt.Sprintf("%s ", w.Branch)
+	} else if w.Detached && w.Metadata != nil && w.Metadata.DetachedAt != "" {
+		desc += warningStyle.Render(fmt.Sprintf("(detached %s @ %s)", w.Metadata.DetachedAt, shortHead(w.Head))) + " "
+	} else if w.Detached {
+		desc += warningStyle.Render(fmt.Sprintf("(detached %s)", shortHead(w.Head))) + " "
+	}
+
+	// State badges
+	if w.Locked {
+		desc += warningStyle.Render("🔒 locked") + " "
+	}
+	if w.Prunable {
+		desc += dirtyStyle.Render("⚠ prunable") + " "
 	}
 
 	// Status indicators
-	if w.Status != nil {
-		if w.Status.Clean {
+	if w.Problem != git.ProblemNone {
+		desc += dirtyStyle.Render(fmt.Sprintf("✗ %s (wtx repair)", w.Problem))
+	} else if w.Status != nil {
+		switch {
+		case w.Status.Operation != git.OperationNone || w.Status.Conflicted > 0:
+			desc += warningStyle.Render("⚠ " + w.Status.State())
+		case w.Status.Clean:
 			desc += cleanStyle.Render("● clean")
-		} else {
+		default:
 			desc += dirtyStyle.Render("✗ dirty")
 		}
 
+		if changes := w.Status.ChangeSummary(); changes != "" {
+			desc += " " + changes
+		}
 		if w.Status.Ahead > 0 {
 			desc += fmt.Sprintf(" ↑%d", w.Status.Ahead)
 		}
 		if w.Status.Behind > 0 {
 			desc += fmt.Sprintf(" ↓%d", w.Status.Behind)
 		}
+		if w.Status.UpstreamGone {
+			desc += dirtyStyle.Render(" ⊘ upstream gone")
+		}
+		if w.StatusPending {
+			desc += " " + w.pendingIndicator()
+		}
+	} else if w.StatusPending {
+		desc += w.pendingIndicator() + helpStyle.Render(" checking")
+		if t := w.Tracking; t != nil {
+			if t.Ahead > 0 {
+				desc += fmt.Sprintf(" ↑%d", t.Ahead)
+			}
+			if t.Behind > 0 {
+				desc += fmt.Sprintf(" ↓%d", t.Behind)
+			}
+			if t.Gone {
+				desc += dirtyStyle.Render(" ⊘ upstream gone")
+			}
+		}
+	}
+	if w.StatusTimedOut {
+		desc += warningStyle.Render(" ⏱ status timed out")
 	}
 
 	// Ports
@@ -54,9 +119,49 @@ func (w WorktreeItem) Description() string {
 		}
 	}
 
+	if c := w.Commit; c != nil {
+		line := fmt.Sprintf("%s · %s · %s", c.Age(time.Now()), c.Subject, c.Author)
+		if c.Base != "" && c.SinceBase > 0 && !w.IsMain {
+			line += fmt.Sprintf(" · %d since %s", c.SinceBase, c.Base)
+		}
+		desc += "\n" + helpStyle.Render(line)
+	}
+
 	return desc
 }
 
+// pendingIndicator returns the spinner frame shown while the status loads
+func (w WorktreeItem) pendingIndicator() string {
+	if w.spinnerFrame == "" {
+		return helpStyle.Render("…")
+	}
+	return w.spinnerFrame
+}
+
+// worktree converts the item back into a git.Worktree
+func (w WorktreeItem) worktree() git.Worktree {
+	return git.Worktree{
+		Name:           w.Name,
+		Path:           w.Path,
+		Branch:         w.Branch,
+		Head:           w.Head,
+		IsMain:         w.IsMain,
+		Detached:       w.Detached,
+		Locked:         w.Locked,
+		LockReason:     w.LockReason,
+		Prunable:       w.Prunable,
+		PrunableReason: w.PrunableReason,
+	}
+}
+
+// shortHead abbreviates a commit SHA for display
+func shortHead(head string) string {
+	if len(head) > 7 {
+		return head[:7]
+	}
+	return head
+}
+
 // FilterValue returns the value used for filtering
 func (w WorktreeItem) FilterValue() string {
 	return w.Name
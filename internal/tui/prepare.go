@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/darkLord19/wtx/internal/git"
+)
+
+// prepareStep is a slow step of preparing a new worktree, such as cloning
+// its submodules, run in the background after the worktree is created
+type prepareStep struct {
+	// running is shown in the status line while the step runs
+	running string
+	// done is reported once it succeeded
+	done string
+	run  func() error
+}
+
+// PrepareMsg reports that a step of preparing a new worktree finished
+type PrepareMsg struct {
+	Name string
+	// Results describes every step finished so far
+	Results []string
+	Failed  bool
+
+	rest []prepareStep
+}
+
+// prepareSteps returns the steps that fetch a new worktree's submodules and
+// LFS files
+func prepareSteps(gitMgr *git.Manager, result *git.AddResult) []prepareStep {
+	var steps []prepareStep
+	if result.HasSubmodules {
+		steps = append(steps, prepareStep{
+			running: "initializing submodules...",
+			done:    "✓ submodules initialized",
+			run:     func() error { return gitMgr.UpdateSubmodules(result.Path, nil) },
+		})
+	}
+	if result.UsesLFS {
+		steps = append(steps, prepareStep{
+			running: "downloading LFS files...",
+			done:    "✓ LFS files downloaded",
+			run:     func() error { return gitMgr.PullLFS(result.Path, nil) },
+		})
+	}
+	return steps
+}
+
+// runPrepareStep returns a command running the first of steps and reporting
+// it in a PrepareMsg that carries the rest
+func runPrepareStep(name string, steps []prepareStep, results []string, failed bool) tea.Cmd {
+	step := steps[0]
+	return func() tea.Msg {
+		result := step.done
+		if err := step.run(); err != nil {
+			result = "✗ " + err.Error()
+			failed = true
+		}
+		return PrepareMsg{
+			Name:    name,
+			Results: append(results, result),
+			Failed:  failed,
+			rest:    steps[1:],
+		}
+	}
+}
+
+// prepared shows the outcome of a step of preparing a new worktree and
+// starts the next one, refreshing the list once they are all done
+func (m *ManageModel) prepared(msg PrepareMsg) (tea.Model, tea.Cmd) {
+	status := fmt.Sprintf("%s: %s", msg.Name, strings.Join(msg.Results, "; "))
+	if len(msg.rest) > 0 {
+		m.SetMessage(status+"; "+msg.rest[0].running, msg.Failed)
+		return m, runPrepareStep(msg.Name, msg.rest, msg.Results, msg.Failed)
+	}
+
+	m.SetMessage(status, msg.Failed)
+	return m.RefreshList()
+}
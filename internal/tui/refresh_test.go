@@ -106,3 +106,143 @@ func TestRefreshList_Async(t *testing.T) {
 		m.Update(msgFromCmd)
 	}
 }
+
+func TestStatusStream(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{}
+	edDetector := editor.NewDetector(cfg)
+
+	m, err := NewManagerModel(gitMgr, metaStore, cfg, edDetector)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// Without a cache every row starts out pending
+	for _, item := range m.items {
+		if !item.StatusPending || item.Status != nil {
+			t.Fatalf("Expected %s to be pending without a status", item.Name)
+		}
+	}
+
+	// Drain the stream, feeding each status back into the model
+	cmd := startStatusStream(gitMgr, m.items)
+	for cmd != nil {
+		msg := cmd()
+		if _, done := msg.(StatusDoneMsg); done {
+			break
+		}
+		if _, ok := msg.(StatusMsg); !ok {
+			t.Fatalf("Expected StatusMsg, got %T", msg)
+		}
+		_, cmd = m.Update(msg)
+	}
+
+	for _, items := range [][]WorktreeItem{m.items, m.manageModel.Items} {
+		for _, item := range items {
+			if item.StatusPending || item.Status == nil {
+				t.Errorf("Expected %s to have a status, got %+v", item.Name, item)
+			}
+		}
+	}
+
+	// The next start renders fresh cached statuses without checking them
+	_, wtItems, err := LoadCachedWorktreeItems(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("LoadCachedWorktreeItems failed: %v", err)
+	}
+	for _, item := range wtItems {
+		if item.StatusPending || item.Status == nil {
+			t.Errorf("Expected fresh cached status for %s, got %+v", item.Name, item)
+		}
+	}
+	if cmd := startStatusStream(gitMgr, wtItems); cmd != nil {
+		t.Error("Expected no status stream when every row is fresh")
+	}
+}
+
+func TestCachedStatusRevalidated(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	// Fill the cache while the worktree is clean
+	if _, _, err := LoadWorktreeItems(gitMgr, metaStore); err != nil {
+		t.Fatalf("LoadWorktreeItems failed: %v", err)
+	}
+
+	// Staging a file changes the index, so the cached status is stale
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "-C", dir, "add", "new.txt").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, items, err := LoadCachedWorktreeItems(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("LoadCachedWorktreeItems failed: %v", err)
+	}
+	if len(items) != 1 || !items[0].StatusPending || items[0].Status == nil {
+		t.Fatalf("Expected the cached row to be a pending placeholder, got %+v", items)
+	}
+
+	cmd := startStatusStream(gitMgr, items)
+	if cmd == nil {
+		t.Fatal("Expected the stale row to be revalidated")
+	}
+	msg, ok := cmd().(StatusMsg)
+	if !ok || msg.Status == nil {
+		t.Fatalf("Expected a StatusMsg with a status, got %+v", msg)
+	}
+	if msg.Status.Clean || msg.Status.Staged != 1 {
+		t.Errorf("Expected 1 staged file, got %+v", msg.Status)
+	}
+
+	// Edits that leave the index alone aren't noticed by the cache, but a
+	// refresh always runs git status
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, items, err = LoadWorktreeItems(gitMgr, metaStore)
+	if err != nil {
+		t.Fatalf("LoadWorktreeItems failed: %v", err)
+	}
+	if s := items[0].Status; s == nil || s.Unstaged != 1 || s.Staged != 1 {
+		t.Errorf("Expected 1 staged and 1 unstaged file, got %+v", s)
+	}
+}
+
+func TestCommitsLoadAsync(t *testing.T) {
+	dir, gitMgr, metaStore := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{}
+	edDetector := editor.NewDetector(cfg)
+
+	m, err := NewManagerModel(gitMgr, metaStore, cfg, edDetector)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// Starting up doesn't wait for the last commits
+	for _, item := range m.items {
+		if item.Commit != nil {
+			t.Fatalf("Expected %s to start without a commit, got %+v", item.Name, item.Commit)
+		}
+	}
+
+	msg, ok := loadCommitsCmd(gitMgr, m.items)().(CommitsMsg)
+	if !ok {
+		t.Fatal("Expected a CommitsMsg")
+	}
+	m.Update(msg)
+
+	for _, items := range [][]WorktreeItem{m.items, m.manageModel.Items} {
+		for _, item := range items {
+			if item.Commit == nil || item.Commit.Subject != "initial" {
+				t.Errorf("Expected %s to show the initial commit, got %+v", item.Name, item.Commit)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -16,11 +17,12 @@ type model struct {
 	quitting  bool
 	gitMgr    *git.Manager
 	metaStore *metadata.Store
+	spinner   spinner.Model
 }
 
 // NewSelector creates a new TUI selector
 func NewSelector(gitMgr *git.Manager, metaStore *metadata.Store) (*model, error) {
-	items, wtItems, err := LoadWorktreeItems(gitMgr, metaStore)
+	items, wtItems, err := LoadCachedWorktreeItems(gitMgr, metaStore)
 	if err != nil {
 		return nil, err
 	}
@@ -32,11 +34,12 @@ func NewSelector(gitMgr *git.Manager, metaStore *metadata.Store) (*model, error)
 		items:     wtItems,
 		gitMgr:    gitMgr,
 		metaStore: metaStore,
+		spinner:   newStatusSpinner(),
 	}, nil
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(startStatusStream(m.gitMgr, m.items), loadCommitsCmd(m.gitMgr, m.items), m.spinner.Tick)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -46,6 +49,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list.SetHeight(msg.Height - 4)
 		return m, nil
 
+	case StatusMsg:
+		applyStatus(&m.list, m.items, msg)
+		return m, msg.Next()
+
+	case CommitsMsg:
+		applyCommits(&m.list, m.items, msg)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		if !applySpinnerFrame(&m.list, m.items, m.spinner.View()) {
+			return m, nil
+		}
+		return m, cmd
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
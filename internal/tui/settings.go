@@ -10,6 +10,7 @@ import (
 
 	"github.com/darkLord19/wtx/internal/config"
 	"github.com/darkLord19/wtx/internal/editor"
+	"github.com/darkLord19/wtx/internal/git"
 )
 
 // SettingType represents the type of setting
@@ -87,7 +88,7 @@ func NewSettingsModel(cfg *config.Config, edDetector *editor.Detector) *settings
 		},
 		{
 			Name:        "Worktree Directory",
-			Description: "Directory where new worktrees are created (relative to repo)",
+			Description: "Where new worktrees are created: a path relative to the repo, a template using {repo}, {name}, {branch} and ~, or a preset (sibling, nested, central)",
 			Type:        SettingWorktreeDir,
 			Value:       cfg.WorktreeDir,
 			Options:     nil, // Text input
@@ -256,6 +257,11 @@ func (m *settingsModel) saveSettings() {
 		case SettingReuseWindow:
 			m.config.ReuseWindow = setting.Value == "true"
 		case SettingWorktreeDir:
+			if err := git.ValidateLayout(setting.Value); err != nil {
+				m.err = fmt.Errorf("invalid worktree directory: %w", err)
+				m.saved = false
+				return
+			}
 			m.config.WorktreeDir = setting.Value
 		case SettingAutoStartDev:
 			m.config.AutoStartDev = setting.Value == "true"
@@ -10,6 +10,7 @@ import (
 
 	"github.com/darkLord19/wtx/internal/config"
 	"github.com/darkLord19/wtx/internal/editor"
+	"github.com/darkLord19/wtx/internal/git"
 )
 
 // SetupStep represents a step in the setup wizard
@@ -40,6 +41,7 @@ type setupModel struct {
 	// Text inputs
 	customEditorInput textinput.Model
 	worktreeDirInput  textinput.Model
+	worktreeDirErr    string
 
 	// Boolean selections
 	reuseWindowValue bool
@@ -200,8 +202,13 @@ func (m *setupModel) handleWorktreeDirKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	case "enter":
 		value := strings.TrimSpace(m.worktreeDirInput.Value())
 		if value != "" {
+			if err := git.ValidateLayout(value); err != nil {
+				m.worktreeDirErr = err.Error()
+				return m, nil
+			}
 			m.config.WorktreeDir = value
 		}
+		m.worktreeDirErr = ""
 		m.worktreeDirInput.Blur()
 		m.step = StepReuseWindow
 		return m, nil
@@ -363,8 +370,14 @@ func (m *setupModel) viewWorktreeDir() string {
 	b.WriteString(m.worktreeDirInput.View())
 	b.WriteString("\n\n")
 	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(
-		"This is relative to your repository root. Default: ../worktrees"))
+		"A path relative to your repository root, or a template using {repo}, {name},\n" +
+			"{branch} and ~ (e.g. ~/worktrees/{repo}/{name}). Presets: sibling, nested, central.\n" +
+			"Default: ../worktrees"))
 	b.WriteString("\n\n")
+	if m.worktreeDirErr != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Render("✗ " + m.worktreeDirErr))
+		b.WriteString("\n\n")
+	}
 	b.WriteString(helpStyle.Render("enter confirm • esc go back"))
 
 	return b.String()
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/darkLord19/wtx/internal/git"
+	"github.com/darkLord19/wtx/internal/logger"
+)
+
+// statusTimeout is how long a single worktree's status may take before its
+// row shows a timeout badge instead
+const statusTimeout = 10 * time.Second
+
+// StatusMsg delivers the status of one worktree as soon as it is known
+type StatusMsg struct {
+	Path     string
+	Status   *git.Status
+	TimedOut bool
+	Err      error
+
+	stream *statusStream
+}
+
+// StatusDoneMsg is sent once every status has been delivered
+type StatusDoneMsg struct{}
+
+// statusStream reads results from git.Manager.StreamStatuses and records
+// them in the status cache
+type statusStream struct {
+	results <-chan git.StatusResult
+	cache   *git.StatusCache
+}
+
+// startStatusStream checks pending items in the background and returns a
+// command delivering the first result, or nil when nothing is pending.
+// Items with a fresh cached status aren't pending and skip git status.
+func startStatusStream(gitMgr *git.Manager, items []WorktreeItem) tea.Cmd {
+	worktrees := make([]git.Worktree, 0, len(items))
+	for _, item := range items {
+		if item.StatusPending {
+			worktrees = append(worktrees, item.worktree())
+		}
+	}
+	if len(worktrees) == 0 {
+		return nil
+	}
+
+	stream := &statusStream{
+		results: gitMgr.StreamStatuses(context.Background(), worktrees, statusTimeout),
+		cache:   git.LoadStatusCache(gitMgr.GitDir()),
+	}
+	return stream.next()
+}
+
+// next returns a command waiting for the stream's next result
+func (s *statusStream) next() tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-s.results
+		if !ok {
+			if err := s.cache.Save(); err != nil {
+				logger.Warn("failed to save status cache: %v", err)
+			}
+			return StatusDoneMsg{}
+		}
+
+		if result.Err == nil {
+			s.cache.Put(result.Worktree, result.Status)
+		}
+		return StatusMsg{
+			Path:     result.Worktree.Path,
+			Status:   result.Status,
+			TimedOut: result.TimedOut(),
+			Err:      result.Err,
+			stream:   s,
+		}
+	}
+}
+
+// Next returns a command waiting for the status after this one
+func (msg StatusMsg) Next() tea.Cmd {
+	if msg.stream == nil {
+		return nil
+	}
+	return msg.stream.next()
+}
+
+// applyStatus updates the item a StatusMsg is for, in both the item slice
+// and the list
+func applyStatus(l *list.Model, items []WorktreeItem, msg StatusMsg) {
+	for i := range items {
+		if items[i].Path != msg.Path {
+			continue
+		}
+
+		items[i].StatusPending = false
+		items[i].StatusTimedOut = msg.TimedOut
+		if msg.Err == nil {
+			items[i].Status = msg.Status
+		}
+		l.SetItem(i, items[i])
+		return
+	}
+}
+
+// applySpinnerFrame shows the spinner's current frame on pending items. It
+// reports whether any item is still pending.
+func applySpinnerFrame(l *list.Model, items []WorktreeItem, frame string) bool {
+	pending := false
+	for i := range items {
+		if !items[i].StatusPending {
+			continue
+		}
+		pending = true
+		items[i].spinnerFrame = frame
+		l.SetItem(i, items[i])
+	}
+	return pending
+}
+
+// newStatusSpinner returns the spinner shown on rows still loading
+func newStatusSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.MiniDot
+	s.Style = helpStyle
+	return s
+}
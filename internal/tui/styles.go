@@ -19,4 +19,7 @@ var (
 
 	portStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#7D56F4"))
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500"))
 )
@@ -3,6 +3,7 @@ package tui
 import (
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/darkLord19/wtx/internal/config"
 	"github.com/darkLord19/wtx/internal/git"
 	"github.com/darkLord19/wtx/internal/metadata"
 )
@@ -26,11 +27,12 @@ type worktreeManagerModel struct {
 }
 
 // NewWorktreeManagerModel creates a new worktree manager TUI model
-func NewWorktreeManagerModel(gitMgr *git.Manager, metaStore *metadata.Store) (*worktreeManagerModel, error) {
+func NewWorktreeManagerModel(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config.Config) (*worktreeManagerModel, error) {
 	manageModel, err := NewManageModel(gitMgr, metaStore)
 	if err != nil {
 		return nil, err
 	}
+	manageModel.DeleteBranchDefault = cfg.DeleteBranch
 
 	return &worktreeManagerModel{
 		manageModel: manageModel,
@@ -38,7 +40,7 @@ func NewWorktreeManagerModel(gitMgr *git.Manager, metaStore *metadata.Store) (*w
 }
 
 func (m *worktreeManagerModel) Init() tea.Cmd {
-	return nil
+	return m.manageModel.Init()
 }
 
 func (m *worktreeManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -79,8 +81,8 @@ func (m *worktreeManagerModel) View() string {
 }
 
 // RunWorktreeManager starts the worktree manager TUI
-func RunWorktreeManager(gitMgr *git.Manager, metaStore *metadata.Store) error {
-	m, err := NewWorktreeManagerModel(gitMgr, metaStore)
+func RunWorktreeManager(gitMgr *git.Manager, metaStore *metadata.Store, cfg *config.Config) error {
+	m, err := NewWorktreeManagerModel(gitMgr, metaStore, cfg)
 	if err != nil {
 		return err
 	}